@@ -4,15 +4,18 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"text/template"
+	"time"
 
 	"git.bacardi55.io/bacardi55/walgot/internal/api"
 	"git.bacardi55.io/bacardi55/walgot/internal/config"
 	"git.bacardi55.io/bacardi55/walgot/internal/tui"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/mitchellh/go-homedir"
 )
 
 // Default config:
@@ -21,6 +24,37 @@ const defaultConfigJSON = "~/.config/walgot/walgot.json"
 const defaultCredentialsFile = "~/.config/walgot/credentials.json"
 const defaultLogFile = "/tmp/walgot.log"
 const defaultNbEntriesPerAPICall = 250
+const defaultNbConcurrentAPICalls = 4
+const defaultMaxOpenAtOnce = 10
+const defaultAPITimeout = 30 * time.Second
+const defaultCacheFile = "/tmp/walgot-cache.dat"
+const defaultStateFile = "state.json"
+const defaultQueueFile = "queue.json"
+const defaultDateFormat = "2006-01-02"
+const defaultContentRenderer = "text"
+const defaultAppearance = "auto"
+const defaultExportPath = "~/walgot/{{.Title}}.md"
+const defaultStartupView = "list"
+const defaultSortField = "created"
+const defaultSortOrder = "desc"
+
+// Minimal configuration, written with -init-config.
+// %s is replaced by the credentials file path.
+const exampleConfigJSON = `{
+    "CredentialsFile": "%s",
+    "LogFile": "/tmp/walgot.log"
+}
+`
+
+// Credentials template, written with -init-config.
+const exampleCredentialsJSON = `{
+    "WallabagURL": "https://your.wallabag.tld",
+    "ClientId": "client ID generated in your profile on wallabag",
+    "ClientSecret": "client secret generated in your profile on wallabag",
+    "UserName": "your username",
+    "UserPassword": "your password"
+}
+`
 
 // WalgotCmd contains command data.
 type WalgotCmd struct {
@@ -36,74 +70,258 @@ func New() *WalgotCmd {
 // Init initialize the application.
 func Init() (*WalgotCmd, error) {
 	// Manage command line flags:
-	configFile, debugMode := handleFlags()
+	flags := handleFlags()
 
 	// Check walgot configuration file path:
-	configFilePath, err := homedir.Expand(*configFile)
+	configFilePath, err := config.ExpandPath(flags.configFile)
 	if err != nil {
-		if *debugMode {
-			fmt.Println("Couldn't find configuration file")
+		if flags.debugMode {
+			fmt.Println("Couldn't find configuration file", err.Error())
 		}
 		return New(), errors.New("couldn't find configuration file")
 	}
 
+	// First run, write configuration templates:
+	if flags.initConfig {
+		if err := writeConfigTemplates(configFilePath); err != nil {
+			return New(), errors.New("couldn't write configuration templates: " + err.Error())
+		}
+		os.Exit(0)
+	}
+
 	// Load walgot configuration from Json file:
 	walgotConfig, err := config.LoadConfig(configFilePath)
-	if err != nil {
-		if *debugMode {
-			fmt.Println("Error loading Walgot configuration", err.Error())
-		}
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Println("Configuration file not found:", configFilePath)
+		fmt.Println("Create it with at least the following content, or run walgot with -init-config to write it:")
+		fmt.Printf("\n"+exampleConfigJSON+"\n", defaultCredentialsFile)
+		return &WalgotCmd{}, errors.New("missing walgot configuration")
+	} else if err != nil {
+		fmt.Println("Invalid configuration file", configFilePath+":", err)
 		return &WalgotCmd{}, errors.New("couldn't load walgot configuration")
 	}
+	// Profile from command line replaces the configured one:
+	if len(flags.profile) > 0 {
+		walgotConfig.Profile = flags.profile
+	}
+	if flags.checkConfig {
+		checkConfig(configFilePath, walgotConfig)
+	}
 
 	// Configure logs before starting:
 	if len(walgotConfig.LogFile) == 0 {
 		walgotConfig.LogFile = defaultLogFile
 	}
-	if err := configLogs(walgotConfig.LogFile); err != nil {
-		if walgotConfig.DebugMode {
-			log.Println(err)
-		}
+	logFilePath, err := config.ExpandPath(walgotConfig.LogFile)
+	if err != nil {
+		fmt.Println("Couldn't determine path for log file", walgotConfig.LogFile)
+		return &WalgotCmd{}, errors.New("error configuring logs")
+	}
+	walgotConfig.LogFile = logFilePath
+	// Last log lines are also kept to be displayed in walgot:
+	logs := tui.NewLogBuffer()
+	if err := configLogs(walgotConfig.LogFile, logs); err != nil {
+		fmt.Println("Couldn't open log file", walgotConfig.LogFile+":", err)
 		return &WalgotCmd{}, errors.New("error configuring logs")
 	}
 
-	// Load credentials file:
-	credentialsFilePath := walgotConfig.CredentialsFile
-	if len(credentialsFilePath) == 0 {
+	// Credentials files, all of them are resolved to switch profile in walgot:
+	if len(walgotConfig.CredentialsFile) == 0 {
 		log.Println("Empty credentialsFile config, using default", defaultCredentialsFile)
 		walgotConfig.CredentialsFile = defaultCredentialsFile
 	}
-	credentialsFilePath, err = homedir.Expand(walgotConfig.CredentialsFile)
+	walgotConfig.CredentialsFile, err = config.ExpandPath(walgotConfig.CredentialsFile)
 	if err != nil {
 		if walgotConfig.DebugMode {
 			fmt.Println(err)
 		}
 		return &WalgotCmd{}, errors.New("couldn't determine path for credentials file")
 	}
+	profiles := map[string]string{}
+	for name, credentialsFile := range walgotConfig.Profiles {
+		if profiles[name], err = config.ExpandPath(credentialsFile); err != nil {
+			fmt.Println("Couldn't determine path for credentials file of profile", name+":", err)
+			return &WalgotCmd{}, errors.New("couldn't determine path for credentials file")
+		}
+	}
+	walgotConfig.Profiles = profiles
+
+	// Load credentials file, of the profile if any:
+	credentialsFilePath, err := walgotConfig.ProfileCredentialsFile(walgotConfig.Profile)
+	if err != nil {
+		fmt.Println("Invalid profile:", err, "- available profiles:", walgotConfig.ProfileNames())
+		return &WalgotCmd{}, errors.New("couldn't load profile")
+	}
 	// Check if file exists, otherwise API will fail and that's it:
 	_, err = os.Stat(credentialsFilePath)
 	if err != nil {
 		if walgotConfig.DebugMode {
 			log.Println(err)
 		}
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Println("Credentials file not found:", credentialsFilePath)
+			fmt.Println("Create it with the following content, or run walgot with -init-config to write it:")
+			fmt.Print("\n" + exampleCredentialsJSON + "\n")
+		}
 		return &WalgotCmd{}, errors.New("couldn't find credentials file")
 	} else if walgotConfig.DebugMode {
 		log.Println("Found credentials file", credentialsFilePath)
 	}
 
-	walgotConfig.CredentialsFile = credentialsFilePath
-
-	// If NbEntriesPerAPICall is not set:
+	// If NbEntriesPerAPICall is not set or too big:
 	if walgotConfig.NbEntriesPerAPICall <= 0 {
 		walgotConfig.NbEntriesPerAPICall = defaultNbEntriesPerAPICall
+	} else if walgotConfig.NbEntriesPerAPICall > config.MaxNbEntriesPerAPICall {
+		log.Println("Warning: NbEntriesPerAPICall", walgotConfig.NbEntriesPerAPICall, "is too big, using", config.MaxNbEntriesPerAPICall)
+		walgotConfig.NbEntriesPerAPICall = config.MaxNbEntriesPerAPICall
+	}
+	// If NbConcurrentAPICalls is not set:
+	if walgotConfig.NbConcurrentAPICalls <= 0 {
+		walgotConfig.NbConcurrentAPICalls = defaultNbConcurrentAPICalls
+	}
+	// If APITimeout is not set:
+	if walgotConfig.APITimeout <= 0 {
+		walgotConfig.APITimeout = defaultAPITimeout
+	}
+	// If MaxOpenAtOnce is not set:
+	if walgotConfig.MaxOpenAtOnce <= 0 {
+		walgotConfig.MaxOpenAtOnce = defaultMaxOpenAtOnce
+	}
+
+	// Cache file:
+	if len(walgotConfig.CacheFile) == 0 {
+		walgotConfig.CacheFile = defaultCacheFile
+	}
+	cacheFilePath, err := config.ExpandPath(walgotConfig.CacheFile)
+	if err != nil {
+		if walgotConfig.DebugMode {
+			log.Println(err)
+		}
+		return &WalgotCmd{}, errors.New("couldn't determine path for cache file")
+	}
+	walgotConfig.CacheFile = cacheFilePath
+	if flags.noCache {
+		walgotConfig.NoCache = true
+	}
+	if flags.offline {
+		walgotConfig.Offline = true
+	}
+	if flags.readOnly {
+		walgotConfig.ReadOnly = true
+	}
+
+	// State file, saved next to the configuration file by default:
+	if len(walgotConfig.StateFile) == 0 {
+		walgotConfig.StateFile = filepath.Join(filepath.Dir(configFilePath), defaultStateFile)
+	}
+	stateFilePath, err := config.ExpandPath(walgotConfig.StateFile)
+	if err != nil {
+		if walgotConfig.DebugMode {
+			log.Println(err)
+		}
+		return &WalgotCmd{}, errors.New("couldn't determine path for state file")
+	}
+	walgotConfig.StateFile = stateFilePath
+
+	// Queue file of changes not sent yet, saved next to the configuration file by default:
+	if len(walgotConfig.QueueFile) == 0 {
+		walgotConfig.QueueFile = filepath.Join(filepath.Dir(configFilePath), defaultQueueFile)
+	}
+	queueFilePath, err := config.ExpandPath(walgotConfig.QueueFile)
+	if err != nil {
+		if walgotConfig.DebugMode {
+			log.Println(err)
+		}
+		return &WalgotCmd{}, errors.New("couldn't determine path for queue file")
+	}
+	walgotConfig.QueueFile = queueFilePath
+	if flags.resetFilters {
+		walgotConfig.ResetFilters = true
+	}
+
+	// Date format, an invalid one shouldn't prevent walgot from starting:
+	if len(walgotConfig.DateFormat) == 0 {
+		walgotConfig.DateFormat = defaultDateFormat
+	} else if !config.IsValidDateFormat(walgotConfig.DateFormat) {
+		log.Println("Warning: invalid DateFormat", walgotConfig.DateFormat, "using default", defaultDateFormat)
+		walgotConfig.DateFormat = defaultDateFormat
+	}
+
+	// Content renderer, "text" or "markdown":
+	if walgotConfig.ContentRenderer != "text" && walgotConfig.ContentRenderer != "markdown" {
+		if len(walgotConfig.ContentRenderer) > 0 {
+			log.Println("Warning: unknown ContentRenderer", walgotConfig.ContentRenderer, "using default", defaultContentRenderer)
+		}
+		walgotConfig.ContentRenderer = defaultContentRenderer
+	}
+
+	// Appearance, "auto", "light" or "dark":
+	if walgotConfig.Appearance != "auto" && walgotConfig.Appearance != "light" && walgotConfig.Appearance != "dark" {
+		if len(walgotConfig.Appearance) > 0 {
+			log.Println("Warning: unknown Appearance", walgotConfig.Appearance, "using default", defaultAppearance)
+		}
+		walgotConfig.Appearance = defaultAppearance
+	}
+
+	// Export path template, an invalid one shouldn't prevent walgot from starting:
+	if len(walgotConfig.ExportPath) == 0 {
+		walgotConfig.ExportPath = defaultExportPath
+	} else if _, err := template.New("export").Parse(walgotConfig.ExportPath); err != nil {
+		log.Println("Warning: invalid ExportPath", walgotConfig.ExportPath, "using default", defaultExportPath)
+		walgotConfig.ExportPath = defaultExportPath
+	}
+	exportPath, err := config.ExpandPath(walgotConfig.ExportPath)
+	if err != nil {
+		if walgotConfig.DebugMode {
+			log.Println(err)
+		}
+		return &WalgotCmd{}, errors.New("couldn't determine path for export files")
+	}
+	walgotConfig.ExportPath = exportPath
+
+	// Default sort, DefaultSorting and DefaultOrder are the former option names:
+	if len(walgotConfig.DefaultSortField) == 0 {
+		walgotConfig.DefaultSortField = walgotConfig.DefaultSorting
+	}
+	if len(walgotConfig.DefaultSortOrder) == 0 {
+		walgotConfig.DefaultSortOrder = walgotConfig.DefaultOrder
+	}
+	switch walgotConfig.DefaultSortField {
+	case "created", "updated", "title", "reading":
+	default:
+		if len(walgotConfig.DefaultSortField) > 0 {
+			log.Println("Warning: unknown DefaultSortField", walgotConfig.DefaultSortField, "using default", defaultSortField)
+		}
+		walgotConfig.DefaultSortField = defaultSortField
+	}
+	if walgotConfig.DefaultSortOrder != "asc" && walgotConfig.DefaultSortOrder != "desc" {
+		if len(walgotConfig.DefaultSortOrder) > 0 {
+			log.Println("Warning: unknown DefaultSortOrder", walgotConfig.DefaultSortOrder, "using default", defaultSortOrder)
+		}
+		walgotConfig.DefaultSortOrder = defaultSortOrder
+	}
+
+	// Startup view and filters, invalid ones are ignored:
+	if walgotConfig.StartupView != "list" && walgotConfig.StartupView != "grouped" && walgotConfig.StartupView != "tags" {
+		if len(walgotConfig.StartupView) > 0 {
+			log.Println("Warning: unknown StartupView", walgotConfig.StartupView, "using default", defaultStartupView)
+		}
+		walgotConfig.StartupView = defaultStartupView
+	}
+	if _, err := walgotConfig.ParseStartupFilter(); err != nil {
+		log.Println("Warning: invalid StartupFilter", walgotConfig.StartupFilter+":", err, "using default filters")
+		walgotConfig.StartupFilter = ""
 	}
 
 	// Initialize wallabago:
-	api.InitWallabagoAPI(walgotConfig.CredentialsFile)
+	if err := api.InitWallabagoAPI(credentialsFilePath, walgotConfig.NbAPIRetries, walgotConfig.APITimeout); err != nil {
+		fmt.Println("Invalid credentials file", credentialsFilePath+":", err)
+		return &WalgotCmd{}, errors.New("couldn't load credentials")
+	}
 
 	// Create bubbletea program:
 	p := tea.NewProgram(
-		tui.NewModel(walgotConfig),
+		tui.NewModel(walgotConfig, logs),
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
@@ -122,12 +340,32 @@ func (cmd WalgotCmd) Run() {
 	}
 }
 
+// Command line flags.
+type walgotFlags struct {
+	configFile   string
+	debugMode    bool
+	noCache      bool
+	offline      bool
+	readOnly     bool
+	resetFilters bool
+	initConfig   bool
+	checkConfig  bool
+	profile      string
+}
+
 // Manage debug flags.
-func handleFlags() (*string, *bool) {
+func handleFlags() walgotFlags {
 	var (
 		version    = flag.Bool("version", false, "get walgot version")
 		debug      = flag.Bool("d", false, "enable debug output")
 		configJSON = flag.String("config", defaultConfigJSON, "file name of config JSON file")
+		noCache    = flag.Bool("no-cache", false, "ignore cached entries and retrieve them from wallabag")
+		offline    = flag.Bool("offline", false, "browse cached entries only, without calling wallabag")
+		readOnly   = flag.Bool("read-only", false, "disable actions updating wallabag (add, update, delete and tags)")
+		reset      = flag.Bool("reset-filters", false, "ignore filters saved from previous session")
+		initConfig = flag.Bool("init-config", false, "write configuration and credentials templates, if missing")
+		check      = flag.Bool("check-config", false, "validate configuration and credentials files, without starting walgot")
+		profile    = flag.String("profile", "", "name of the profile to use, as configured in Profiles")
 	)
 	flag.Parse()
 	if *version {
@@ -138,17 +376,100 @@ func handleFlags() (*string, *bool) {
 		fmt.Println("handleFlags: debug mode")
 	}
 
-	return configJSON, debug
+	return walgotFlags{
+		configFile:   *configJSON,
+		debugMode:    *debug,
+		noCache:      *noCache,
+		offline:      *offline,
+		readOnly:     *readOnly,
+		resetFilters: *reset,
+		initConfig:   *initConfig,
+		checkConfig:  *check,
+		profile:      *profile,
+	}
+}
+
+// Validate configuration and credentials, print a report and exit.
+// Exit status is 1 if any problem is found.
+func checkConfig(configFilePath string, walgotConfig config.WalgotConfig) {
+	problems := walgotConfig.Validate()
+	problems = append(problems, tui.ConfigWarnings(walgotConfig)...)
+
+	// Default credentials file isn't used with a profile, profiles are checked below:
+	if len(walgotConfig.Profile) == 0 {
+		credentialsFile := walgotConfig.CredentialsFile
+		if len(credentialsFile) == 0 {
+			credentialsFile = defaultCredentialsFile
+		}
+		if credentialsFilePath, err := config.ExpandPath(credentialsFile); err != nil {
+			problems = append(problems, "CredentialsFile: "+err.Error())
+		} else {
+			problems = append(problems, config.ValidateCredentials(credentialsFilePath)...)
+		}
+	}
+	for _, name := range walgotConfig.ProfileNames() {
+		credentialsFilePath, err := config.ExpandPath(walgotConfig.Profiles[name])
+		if err != nil {
+			problems = append(problems, "Profile "+name+": "+err.Error())
+			continue
+		}
+		for _, p := range config.ValidateCredentials(credentialsFilePath) {
+			problems = append(problems, "Profile "+name+": "+p)
+		}
+	}
+
+	if len(problems) == 0 {
+		fmt.Println("Configuration is valid:", configFilePath)
+		os.Exit(0)
+	}
+	fmt.Println(len(problems), "problem(s) found in configuration", configFilePath+":")
+	for _, p := range problems {
+		fmt.Println("-", p)
+	}
+	os.Exit(1)
+}
+
+// Write configuration and credentials templates, existing files are kept.
+// Credentials file is written next to the configuration file.
+func writeConfigTemplates(configFilePath string) error {
+	credentialsFilePath := filepath.Join(filepath.Dir(configFilePath), "credentials.json")
+	templates := []struct {
+		path    string
+		content string
+	}{
+		{configFilePath, fmt.Sprintf(exampleConfigJSON, credentialsFilePath)},
+		{credentialsFilePath, exampleCredentialsJSON},
+	}
+
+	for _, t := range templates {
+		if _, err := os.Stat(t.path); err == nil {
+			fmt.Println("File already exists, keeping it:", t.path)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(t.path), 0700); err != nil {
+			return err
+		}
+		if err := os.WriteFile(t.path, []byte(t.content), 0600); err != nil {
+			return err
+		}
+		fmt.Println("Template written:", t.path)
+	}
+	fmt.Println("Edit the credentials file with your wallabag information, then start walgot.")
+
+	return nil
 }
 
-// Manage log configuration.
-func configLogs(logFile string) error {
+// Manage log configuration, logs are written to the log file and to buffer.
+func configLogs(logFile string, buffer io.Writer) error {
 	fmt.Println("Setting log file:", logFile)
-	file, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	if err := os.MkdirAll(filepath.Dir(logFile), 0700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
 	if err != nil {
 		return err
 	}
 
-	log.SetOutput(file)
+	log.SetOutput(io.MultiWriter(file, buffer))
 	return nil
 }
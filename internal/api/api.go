@@ -1,22 +1,58 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
+	"fmt"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Strubbl/wallabago/v7"
 )
 
 // InitWallabagoAPI set wallabago config.
-func InitWallabagoAPI(credentialsFile string) error {
+// Failing API calls are retried up to retries times on transient errors.
+// HTTP requests (including wallabago ones) fail after timeout, 0 means no timeout.
+func InitWallabagoAPI(credentialsFile string, retries int, timeout time.Duration) error {
+	nbRetries = retries
+	httpClient.Timeout = timeout
 	return wallabago.ReadConfig(credentialsFile)
 }
 
+// SwitchCredentials uses the given credentials file for the next API calls,
+// eg: to use another wallabag account. The current credentials are kept on error.
+func SwitchCredentials(credentialsFile string) error {
+	previous := wallabago.Config
+	if err := wallabago.ReadConfig(credentialsFile); err != nil {
+		wallabago.SetConfig(previous)
+		return err
+	}
+	resetToken()
+
+	return nil
+}
+
+// GetEndpoint returns the wallabag host and user name used for API calls.
+// Credentials are never returned.
+func GetEndpoint() (string, string) {
+	return getURLHost(wallabago.Config.WallabagURL), wallabago.Config.UserName
+}
+
+// Return the host of an URL, without scheme, credentials or path.
+func getURLHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
 // GetEntries returns entries from wallabag APIs.
-func GetEntries(itemsPerPage, pageNumber int, sortField, sortOrder string) (wallabago.Entries, error) {
+func GetEntries(ctx context.Context, itemsPerPage, pageNumber int, sortField, sortOrder string) (wallabago.Entries, error) {
 	return wallabago.GetEntries(
-		wallabago.APICall,
+		apiCaller(ctx),
 		-1,
 		-1,
 		sortField,
@@ -27,13 +63,83 @@ func GetEntries(itemsPerPage, pageNumber int, sortField, sortOrder string) (wall
 	)
 }
 
+// GetEntriesPage returns a page of entries matching the given filters from wallabag APIs.
+// archive, starred and public filters are ignored if negative, tag if empty.
+func GetEntriesPage(ctx context.Context, itemsPerPage, pageNumber, archive, starred, public int, tag, sortField, sortOrder string) (wallabago.Entries, error) {
+	var e wallabago.Entries
+	params := url.Values{}
+	params.Set("page", strconv.Itoa(pageNumber))
+	params.Set("perPage", strconv.Itoa(itemsPerPage))
+	if archive >= 0 {
+		params.Set("archive", strconv.Itoa(archive))
+	}
+	if starred >= 0 {
+		params.Set("starred", strconv.Itoa(starred))
+	}
+	if public >= 0 {
+		params.Set("public", strconv.Itoa(public))
+	}
+	if tag != "" {
+		params.Set("tags", tag)
+	}
+	if sortField != "" {
+		params.Set("sort", sortField)
+	}
+	if sortOrder != "" {
+		params.Set("order", sortOrder)
+	}
+
+	body, err := apiCall(ctx, wallabago.Config.WallabagURL+"/api/entries.json?"+params.Encode(), "GET", nil)
+	if err != nil {
+		return e, err
+	}
+	err = json.Unmarshal(body, &e)
+
+	return e, err
+}
+
+// SearchEntries returns entries matching the given term from wallabag APIs.
+func SearchEntries(ctx context.Context, term string, pageNumber, itemsPerPage int) (wallabago.Entries, error) {
+	var e wallabago.Entries
+	searchURL := wallabago.Config.WallabagURL +
+		"/api/search.json?term=" + url.QueryEscape(term) +
+		"&page=" + strconv.Itoa(pageNumber) +
+		"&perPage=" + strconv.Itoa(itemsPerPage)
+
+	body, err := apiCall(ctx, searchURL, "GET", nil)
+	if err != nil {
+		return e, err
+	}
+	err = json.Unmarshal(body, &e)
+
+	return e, err
+}
+
 // GetNbTotalEntries returns the total number of entries saved in wallabag.
-func GetNbTotalEntries() (int, error) {
-	return wallabago.GetNumberOfTotalArticles()
+func GetNbTotalEntries(ctx context.Context) (int, error) {
+	e, err := wallabago.GetEntries(apiCaller(ctx), -1, -1, "", "", 1, 1, "")
+	if err != nil {
+		return -1, err
+	}
+	return e.Total, nil
+}
+
+// GetAnnotations returns annotations (highlights and notes) of an entry from wallabag APIs.
+func GetAnnotations(ctx context.Context, entryID int) ([]wallabago.Annotation, error) {
+	a, err := wallabago.GetAnnotations(apiCaller(ctx), entryID)
+	if err != nil {
+		return nil, err
+	}
+	return a.Rows, nil
+}
+
+// GetEntry returns an entry, as currently saved on wallabag.
+func GetEntry(ctx context.Context, entryID int) (wallabago.Item, error) {
+	return wallabago.GetEntry(apiCaller(ctx), entryID)
 }
 
 // UpdateEntry update an article on wallabag.
-func UpdateEntry(entryID, archive, starred, public int) ([]byte, error) {
+func UpdateEntry(ctx context.Context, entryID, archive, starred, public int) ([]byte, error) {
 	tmp := map[string]string{
 		"archive": strconv.Itoa(archive),
 		"starred": strconv.Itoa(starred),
@@ -42,15 +148,97 @@ func UpdateEntry(entryID, archive, starred, public int) ([]byte, error) {
 	body, _ := json.Marshal(tmp)
 	url := wallabago.Config.WallabagURL + "/api/entries/" + strconv.Itoa(entryID) + ".json"
 	// Send request and return result:
-	return wallabago.APICall(
+	return apiCall(
+		ctx,
 		url,
 		"PATCH",
 		body,
 	)
 }
 
+// AddTags adds tags to an entry on wallabag, the updated entry is returned.
+func AddTags(ctx context.Context, entryID int, tags []string) (wallabago.Item, error) {
+	postData, err := json.Marshal(map[string]string{
+		"tags": strings.Join(tags, ","),
+	})
+	if err != nil {
+		return wallabago.Item{}, err
+	}
+	tagsURL := wallabago.Config.WallabagURL + "/api/entries/" + strconv.Itoa(entryID) + "/tags.json"
+	body, err := apiCall(ctx, tagsURL, "POST", postData)
+	if err != nil {
+		return wallabago.Item{}, err
+	}
+
+	var item wallabago.Item
+	err = json.Unmarshal(body, &item)
+
+	return item, err
+}
+
+// DeleteTag removes a tag from an entry on wallabag, the updated entry is returned.
+func DeleteTag(ctx context.Context, entryID, tagID int) (wallabago.Item, error) {
+	tagURL := wallabago.Config.WallabagURL +
+		"/api/entries/" + strconv.Itoa(entryID) +
+		"/tags/" + strconv.Itoa(tagID) + ".json"
+	body, err := apiCall(ctx, tagURL, "DELETE", nil)
+	if err != nil {
+		return wallabago.Item{}, err
+	}
+
+	var item wallabago.Item
+	err = json.Unmarshal(body, &item)
+
+	return item, err
+}
+
+// SetTags replaces the tags of an entry on wallabag with the given labels.
+// Only missing tags are added and extra ones removed, the updated entry is returned.
+func SetTags(ctx context.Context, entry wallabago.Item, labels []string) (wallabago.Item, error) {
+	add, remove := diffTags(entry.Tags, labels)
+	updated := entry
+	var err error
+	if len(add) > 0 {
+		if updated, err = AddTags(ctx, entry.ID, add); err != nil {
+			return entry, err
+		}
+	}
+	for _, tag := range remove {
+		if updated, err = DeleteTag(ctx, entry.ID, tag.ID); err != nil {
+			return entry, err
+		}
+	}
+
+	return updated, nil
+}
+
+// Return labels missing from current tags, and current tags missing from labels.
+// Labels are compared case insensitively, as wallabag does.
+func diffTags(current []wallabago.Tag, labels []string) ([]string, []wallabago.Tag) {
+	wanted := map[string]bool{}
+	for _, l := range labels {
+		wanted[strings.ToLower(l)] = true
+	}
+	existing := map[string]bool{}
+	var remove []wallabago.Tag
+	for _, t := range current {
+		existing[strings.ToLower(t.Label)] = true
+		if !wanted[strings.ToLower(t.Label)] {
+			remove = append(remove, t)
+		}
+	}
+	var add []string
+	for _, l := range labels {
+		if !existing[strings.ToLower(l)] {
+			add = append(add, l)
+		}
+	}
+
+	return add, remove
+}
+
 // AddEntry add an entry on wallabag.
-func AddEntry(url string) (wallabago.Item, error) {
+func AddEntry(ctx context.Context, url string) (wallabago.Item, error) {
 	postData := map[string]string{
 		"url": url,
 	}
@@ -59,7 +247,7 @@ func AddEntry(url string) (wallabago.Item, error) {
 		return wallabago.Item{}, err
 	}
 	entriesURL := wallabago.Config.WallabagURL + "/api/entries.json"
-	body, err := wallabago.APICall(entriesURL, "POST", postDataJSON)
+	body, err := apiCall(ctx, entriesURL, "POST", postDataJSON)
 	if err != nil {
 		return wallabago.Item{}, err
 	}
@@ -74,18 +262,19 @@ func AddEntry(url string) (wallabago.Item, error) {
 }
 
 // DeleteEntry removes an entry from wallabag.
-func DeleteEntry(id int) error {
+func DeleteEntry(ctx context.Context, id int) error {
 	url := wallabago.Config.WallabagURL +
 		"/api/entries/" +
 		strconv.Itoa(id)
 
-	_, err := wallabago.APICall(
+	_, err := apiCall(
+		ctx,
 		url,
 		"DELETE",
 		[]byte{},
 	)
 	if err != nil {
-		return errors.New("Couldn't delete entry:" + strconv.Itoa(id))
+		return fmt.Errorf("Couldn't delete entry: %d: %w", id, err)
 	}
 
 	return nil
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Number of retries for failing API calls with a transient error.
+var nbRetries = 0
+
+// Delay before the first retry, doubled for each new retry.
+var retryBaseDelay = 500 * time.Millisecond
+
+// HTTP client of API calls, its timeout is set at init.
+var httpClient = &http.Client{}
+
+// Error returned when wallabag API doesn't respond with a 200.
+type statusError struct {
+	StatusCode int
+	Status     string
+	// Retry-After header, wallabag (or a proxy) asks to send the request later:
+	RetryAfter string
+}
+
+func (e *statusError) Error() string {
+	return "unexpected response from wallabag API: " + e.Status
+}
+
+// Send an authenticated request to wallabag API, retrying on transient errors.
+// The request is aborted as soon as ctx is canceled.
+func apiCall(ctx context.Context, apiURL, httpMethod string, postData []byte) ([]byte, error) {
+	return withRetry(ctx, nbRetries, isIdempotentMethod(httpMethod), func() ([]byte, error) {
+		return doAPICall(ctx, apiURL, httpMethod, postData)
+	})
+}
+
+// Return a wallabago.BodyByteGetter sending requests with the given context.
+func apiCaller(ctx context.Context) func(string, string, []byte) ([]byte, error) {
+	return func(apiURL, httpMethod string, postData []byte) ([]byte, error) {
+		return apiCall(ctx, apiURL, httpMethod, postData)
+	}
+}
+
+// Send an authenticated request to wallabag API.
+// Similar to wallabago.APICall, but non 200 responses are errors
+// and the token is managed by walgot.
+func doAPICall(ctx context.Context, apiURL, httpMethod string, postData []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, httpMethod, apiURL, bytes.NewReader(postData))
+	if err != nil {
+		return nil, err
+	}
+	authString, err := getAuthHeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Authorization", authString)
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &statusError{resp.StatusCode, resp.Status, resp.Header.Get("Retry-After")}
+	}
+
+	return body, nil
+}
+
+// Call the given function, retrying with an exponential backoff
+// as long as the error is transient and retries are left.
+// Calls that aren't idempotent are retried only if they were rejected (see isRejectedError),
+// otherwise they may have been applied (eg: timeout, gateway error).
+// The last error is returned if all retries failed.
+// Retries stop when ctx is canceled, returning the context error.
+func withRetry(ctx context.Context, retries int, idempotent bool, call func() ([]byte, error)) ([]byte, error) {
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		body, err := call()
+		if err == nil || attempt >= retries || !isTransientError(err) || !idempotent && !isRejectedError(err) {
+			return body, err
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// Check if sending the request again has the same effect as sending it once.
+func isIdempotentMethod(httpMethod string) bool {
+	return httpMethod == http.MethodGet || httpMethod == http.MethodPatch
+}
+
+// Check if the request was rejected without being applied: too many requests,
+// or service unavailable with a Retry-After header.
+func isRejectedError(err error) bool {
+	var statusErr *statusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+
+	return statusErr.StatusCode == http.StatusTooManyRequests ||
+		statusErr.StatusCode == http.StatusServiceUnavailable && statusErr.RetryAfter != ""
+}
+
+// Check if the error is worth retrying (timeouts, server errors).
+func isTransientError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	return false
+}
+
+// IsUnreachableError checks if wallabag couldn't be reached or failed to answer
+// (network errors, timeouts, server errors): the call may succeed later.
+// Canceled calls and errors returned by wallabag (eg: not found) are not.
+func IsUnreachableError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return isTransientError(err)
+	}
+	var netErr net.Error
+
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// Timeout error, implementing net.Error.
+type timeoutError struct{}
+
+func (e timeoutError) Error() string   { return "timeout" }
+func (e timeoutError) Timeout() bool   { return true }
+func (e timeoutError) Temporary() bool { return true }
+
+func TestIsTransientError(t *testing.T) {
+	var tests = []struct {
+		inputError  error
+		expectedRes bool
+	}{
+		{timeoutError{}, true},
+		{&statusError{http.StatusInternalServerError, "500 Internal Server Error", ""}, true},
+		{&statusError{http.StatusBadGateway, "502 Bad Gateway", ""}, true},
+		{&statusError{http.StatusTooManyRequests, "429 Too Many Requests", ""}, true},
+		{&statusError{http.StatusNotFound, "404 Not Found", ""}, false},
+		{&statusError{http.StatusUnauthorized, "401 Unauthorized", ""}, false},
+		{errors.New("invalid JSON"), false},
+	}
+
+	for _, test := range tests {
+		result := isTransientError(test.inputError)
+		if test.expectedRes != result {
+			t.Errorf("isTransientError(%v): expected %v, got %v", test.inputError, test.expectedRes, result)
+		}
+	}
+}
+
+func TestIsUnreachableError(t *testing.T) {
+	var tests = []struct {
+		inputError  error
+		expectedRes bool
+	}{
+		{timeoutError{}, true},
+		{&url.Error{Op: "Get", URL: "https://wallabag.example.com", Err: timeoutError{}}, true},
+		{fmt.Errorf("Couldn't delete entry: 1: %w", &statusError{http.StatusBadGateway, "502 Bad Gateway", ""}), true},
+		{&statusError{http.StatusNotFound, "404 Not Found", ""}, false},
+		{context.Canceled, false},
+		{errors.New("invalid JSON"), false},
+	}
+
+	for _, test := range tests {
+		result := IsUnreachableError(test.inputError)
+		if test.expectedRes != result {
+			t.Errorf("IsUnreachableError(%v): expected %v, got %v", test.inputError, test.expectedRes, result)
+		}
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	previousDelay := retryBaseDelay
+	t.Cleanup(func() { retryBaseDelay = previousDelay })
+	retryBaseDelay = 0
+	serverError := &statusError{http.StatusServiceUnavailable, "503 Service Unavailable", ""}
+	unavailable := &statusError{http.StatusServiceUnavailable, "503 Service Unavailable", "120"}
+	gatewayTimeout := &statusError{http.StatusGatewayTimeout, "504 Gateway Timeout", ""}
+	notFound := &statusError{http.StatusNotFound, "404 Not Found", ""}
+
+	var tests = []struct {
+		inputRetries      int
+		inputIdempotent   bool
+		inputErrors       []error
+		expectedNbCalls   int
+		expectedLastError error
+	}{
+		{0, true, []error{nil}, 1, nil},
+		{3, true, []error{nil}, 1, nil},
+		{0, true, []error{serverError}, 1, serverError},
+		{2, true, []error{serverError, nil}, 2, nil},
+		{2, true, []error{serverError, timeoutError{}, nil}, 3, nil},
+		{2, true, []error{serverError, serverError, serverError, nil}, 3, serverError},
+		{2, true, []error{notFound, nil}, 1, notFound},
+		// Timed out call (or behind a proxy) may have been applied:
+		{2, false, []error{timeoutError{}, nil}, 1, timeoutError{}},
+		{2, false, []error{gatewayTimeout, nil}, 1, gatewayTimeout},
+		{2, false, []error{serverError, nil}, 1, serverError},
+		// Rejected call wasn't applied:
+		{2, false, []error{unavailable, nil}, 2, nil},
+	}
+
+	for _, test := range tests {
+		nbCalls := 0
+		_, err := withRetry(context.Background(), test.inputRetries, test.inputIdempotent, func() ([]byte, error) {
+			err := test.inputErrors[nbCalls]
+			nbCalls++
+			return nil, err
+		})
+		if test.expectedNbCalls != nbCalls {
+			t.Errorf("withRetry(%v, %v, %v): expectedNbCalls %v, got %v", test.inputRetries, test.inputIdempotent, test.inputErrors, test.expectedNbCalls, nbCalls)
+		}
+		if test.expectedLastError != err {
+			t.Errorf("withRetry(%v, %v, %v): expectedLastError %v, got %v", test.inputRetries, test.inputIdempotent, test.inputErrors, test.expectedLastError, err)
+		}
+	}
+}
+
+func TestWithRetryCanceled(t *testing.T) {
+	previousDelay := retryBaseDelay
+	t.Cleanup(func() { retryBaseDelay = previousDelay })
+	retryBaseDelay = 0
+	serverError := &statusError{http.StatusServiceUnavailable, "503 Service Unavailable", ""}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	nbCalls := 0
+	_, err := withRetry(ctx, 3, true, func() ([]byte, error) {
+		nbCalls++
+		return nil, serverError
+	})
+	if nbCalls != 1 {
+		t.Errorf("withRetry(canceled): expectedNbCalls 1, got %v", nbCalls)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("withRetry(canceled): expected %v, got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Strubbl/wallabago/v7"
+)
+
+// A new token is requested a bit before the current one expires.
+const tokenExpirationMargin = 30 * time.Second
+
+// Access token for wallabag API.
+// wallabago keeps its token for the whole process, it can't be used when
+// switching between wallabag accounts.
+type accessToken struct {
+	Header     string
+	Expiration time.Time
+}
+
+var (
+	tokenMutex   sync.Mutex
+	currentToken *accessToken
+)
+
+// Return the Authorization header of API calls, a new token is requested
+// with user credentials if there is none yet or if it has expired.
+// Concurrent calls wait for the token requested by the first one.
+func getAuthHeader(ctx context.Context) (string, error) {
+	tokenMutex.Lock()
+	defer tokenMutex.Unlock()
+
+	if currentToken != nil && time.Now().Add(tokenExpirationMargin).Before(currentToken.Expiration) {
+		return currentToken.Header, nil
+	}
+	token, err := requestToken(ctx, wallabago.Config)
+	if err != nil {
+		return "", err
+	}
+	currentToken = token
+
+	return token.Header, nil
+}
+
+// Forget the current token, the next API call requests a new one.
+func resetToken() {
+	tokenMutex.Lock()
+	defer tokenMutex.Unlock()
+	currentToken = nil
+}
+
+// Request an access token from wallabag API, with user credentials.
+func requestToken(ctx context.Context, credentials wallabago.WallabagConfig) (*accessToken, error) {
+	form := url.Values{
+		"grant_type":    {"password"},
+		"client_id":     {credentials.ClientID},
+		"client_secret": {credentials.ClientSecret},
+		"username":      {credentials.UserName},
+		"password":      {credentials.UserPassword},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, credentials.WallabagURL+"/oauth/v2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &statusError{resp.StatusCode, resp.Status, resp.Header.Get("Retry-After")}
+	}
+
+	var r struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, err
+	}
+	if len(r.AccessToken) == 0 {
+		return nil, errors.New("no access token in wallabag API response")
+	}
+
+	return &accessToken{
+		Header:     "Bearer " + r.AccessToken,
+		Expiration: time.Now().Add(time.Duration(r.ExpiresIn) * time.Second),
+	}, nil
+}
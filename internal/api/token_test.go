@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Strubbl/wallabago/v7"
+)
+
+func TestGetAuthHeader(t *testing.T) {
+	nbRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nbRequests++
+		if r.FormValue("username") != "bob" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"access_token": "token%d", "expires_in": 3600}`, nbRequests)
+	}))
+	defer server.Close()
+
+	var tests = []struct {
+		inputUser        string
+		inputReset       bool
+		expected         string
+		expectedRequests int
+		expectedIsErrNil bool
+	}{
+		{"bob", false, "Bearer token1", 1, true},
+		// Token is kept until it expires:
+		{"bob", false, "Bearer token1", 1, true},
+		{"bob", true, "Bearer token2", 2, true},
+		{"alice", true, "", 3, false},
+	}
+
+	for _, test := range tests {
+		wallabago.SetConfig(wallabago.WallabagConfig{WallabagURL: server.URL, UserName: test.inputUser})
+		if test.inputReset {
+			resetToken()
+		}
+		result, err := getAuthHeader(context.Background())
+		if result != test.expected {
+			t.Errorf("getAuthHeader(%v, %v): expected %v, got %v", test.inputUser, test.inputReset, test.expected, result)
+		}
+		if nbRequests != test.expectedRequests {
+			t.Errorf("getAuthHeader(%v, %v): expectedRequests %v, got %v", test.inputUser, test.inputReset, test.expectedRequests, nbRequests)
+		}
+		if (err == nil) != test.expectedIsErrNil {
+			t.Errorf("getAuthHeader(%v, %v): expectedIsErrNil %v, got %v", test.inputUser, test.inputReset, test.expectedIsErrNil, err)
+		}
+	}
+	resetToken()
+}
@@ -0,0 +1,26 @@
+package clipboard
+
+import (
+	"errors"
+
+	"github.com/atotto/clipboard"
+)
+
+// Write copies the given text to the system clipboard.
+// Relies on xclip, xsel or wl-copy on linux, pbcopy on macOS.
+func Write(text string) error {
+	if clipboard.Unsupported {
+		return errors.New("no clipboard utility found")
+	}
+
+	return clipboard.WriteAll(text)
+}
+
+// Read returns the content of the system clipboard.
+func Read() (string, error) {
+	if clipboard.Unsupported {
+		return "", errors.New("no clipboard utility found")
+	}
+
+	return clipboard.ReadAll()
+}
@@ -3,20 +3,128 @@ package config
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Strubbl/wallabago/v7"
+	"github.com/mitchellh/go-homedir"
 )
 
+var profileNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// MaxNbEntriesPerAPICall is the maximum number of entries retrieved per API call,
+// bigger pages are slow and may be rejected by wallabag servers.
+const MaxNbEntriesPerAPICall = 300
+
 // WalgotConfig contains all configuration data.
 type WalgotConfig struct {
 	CredentialsFile        string
+	Profile                string
+	Profiles               map[string]string
 	DefaultListViewUnread  bool
 	DefaultListViewStarred bool
 	DefaultListViewPublic  bool
 	DebugMode              bool
 	LogFile                string
 	NbEntriesPerAPICall    int
+	NbConcurrentAPICalls   int
+	NbAPIRetries           int
+	MaxOpenAtOnce          int
 	DefaultSorting         string
 	DefaultOrder           string
+	DefaultSortField       string
+	DefaultSortOrder       string
+	CacheFile              string
+	CacheTTL               time.Duration
+	APITimeout             time.Duration
+	AutoRefreshInterval    time.Duration
+	NotifyNewEntries       bool
+	ConfirmQuit            bool
+	NoCache                bool
+	Offline                bool
+	ReadOnly               bool
+	ShowEmptyTags          bool
+	ShowTagsColumn         bool
+	ShowExcerptColumn      bool
+	Columns                []string
+	ListDensity            string
+	NoPreview              bool
+	StateFile              string
+	QueueFile              string
+	ResetFilters           bool
+	Keybindings            map[string]string
+	DateFormat             string
+	RelativeDates          bool
+	ReadingWidth           int
+	ListWidth              int
+	PageJumpRows           int
+	ContentRenderer        string
+	NoLinkReferences       bool
+	TrimContent            bool
+	TrimPatterns           []string
+	PaginatedMode          bool
+	Theme                  map[string]string
+	Appearance             string
+	SpinnerStyle           string
+	ShowStatusLine         bool
+	ExportPath             string
+	StartupView            string
+	StartupFilter          string
+}
+
+// StartupFilters are the filters applied on start, parsed from StartupFilter.
+type StartupFilters struct {
+	Unread   bool
+	Starred  bool
+	Archived bool
+	Public   bool
+	Tag      string
+	Search   string
+}
+
+// UnmarshalJSON parses durations written as strings (eg: "15m").
+func (c *WalgotConfig) UnmarshalJSON(raw []byte) error {
+	type walgotConfigAlias WalgotConfig
+	tmp := struct {
+		*walgotConfigAlias
+		CacheTTL            string
+		APITimeout          string
+		AutoRefreshInterval string
+	}{
+		walgotConfigAlias: (*walgotConfigAlias)(c),
+	}
+	if err := json.Unmarshal(raw, &tmp); err != nil {
+		return err
+	}
+
+	ttl, err := parseDuration(tmp.CacheTTL)
+	if err != nil {
+		return errors.New("invalid CacheTTL: " + err.Error())
+	}
+	c.CacheTTL = ttl
+
+	timeout, err := parseDuration(tmp.APITimeout)
+	if err != nil {
+		return errors.New("invalid APITimeout: " + err.Error())
+	}
+	c.APITimeout = timeout
+
+	interval, err := parseDuration(tmp.AutoRefreshInterval)
+	if err != nil {
+		return errors.New("invalid AutoRefreshInterval: " + err.Error())
+	}
+	c.AutoRefreshInterval = interval
+
+	return nil
 }
 
 // LoadConfig will read a given configJSON file and parses the result, returning a parsed config object
@@ -27,6 +135,264 @@ func LoadConfig(configJSON string) (WalgotConfig, error) {
 	return config, err
 }
 
+// ExpandPath returns an absolute path for the given one.
+// Environment variables (eg: $HOME) and a leading "~" are expanded,
+// relative paths are resolved from the current directory.
+// "~user" paths are not supported.
+func ExpandPath(path string) (string, error) {
+	if len(path) == 0 {
+		return "", errors.New("empty path")
+	}
+
+	path = os.ExpandEnv(path)
+	if strings.HasPrefix(path, "~") {
+		if path != "~" && !strings.HasPrefix(path, "~/") {
+			return "", errors.New("cannot expand home directory of another user: " + path)
+		}
+		p, err := homedir.Expand(path)
+		if err != nil {
+			return "", err
+		}
+		path = p
+	}
+
+	return filepath.Abs(path)
+}
+
+// ProfileCredentialsFile returns the credentials file of the given profile.
+// An empty profile name returns CredentialsFile.
+func (c WalgotConfig) ProfileCredentialsFile(profile string) (string, error) {
+	if len(profile) == 0 {
+		return c.CredentialsFile, nil
+	}
+	credentialsFile, ok := c.Profiles[profile]
+	if !ok {
+		return "", errors.New("unknown profile " + profile)
+	}
+
+	return credentialsFile, nil
+}
+
+// ProfileNames returns configured profile names, sorted.
+func (c WalgotConfig) ProfileNames() []string {
+	names := []string{}
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ProfileFilePath returns the path of a file (eg: cache) for the given profile,
+// the profile name is added before the extension: "/tmp/cache-work.dat".
+// Path is unchanged for an empty profile name.
+func ProfileFilePath(path, profile string) string {
+	if len(profile) == 0 {
+		return path
+	}
+	ext := filepath.Ext(path)
+
+	return strings.TrimSuffix(path, ext) + "-" + profile + ext
+}
+
+// IsValidProfileName checks if the given name can be used in file names.
+func IsValidProfileName(name string) bool {
+	return profileNameRegexp.MatchString(name)
+}
+
+// IsValidDateFormat checks if the given layout can be used to format dates.
+// Layouts must follow go time format, eg: "2006-01-02" or "02/01/2006".
+func IsValidDateFormat(layout string) bool {
+	if len(strings.TrimSpace(layout)) == 0 {
+		return false
+	}
+	ref := time.Date(2021, time.November, 23, 20, 45, 30, 0, time.UTC)
+	formatted := ref.Format(layout)
+	// Layout without any date or time element:
+	if formatted == layout {
+		return false
+	}
+	_, err := time.Parse(layout, formatted)
+	return err == nil
+}
+
+// ParseStartupFilter parses StartupFilter, a comma separated list of filters:
+// "all", "unread", "starred", "archived", "public", "tag:<label>" and "search:<term>".
+// Unread and archived filters can't be combined.
+func (c WalgotConfig) ParseStartupFilter() (StartupFilters, error) {
+	var f StartupFilters
+	for _, filter := range strings.Split(c.StartupFilter, ",") {
+		filter = strings.TrimSpace(filter)
+		name, value := filter, ""
+		if i := strings.Index(filter, ":"); i >= 0 {
+			name, value = filter[:i], strings.TrimSpace(filter[i+1:])
+		}
+		switch name {
+		case "", "all":
+		case "unread":
+			f.Unread = true
+		case "starred":
+			f.Starred = true
+		case "archived":
+			f.Archived = true
+		case "public":
+			f.Public = true
+		case "tag", "search":
+			if value == "" {
+				return StartupFilters{}, errors.New("missing value for " + name + " filter")
+			}
+			if name == "tag" {
+				f.Tag = value
+			} else {
+				f.Search = value
+			}
+		default:
+			return StartupFilters{}, errors.New("unknown filter " + filter)
+		}
+	}
+	if f.Unread && f.Archived {
+		return StartupFilters{}, errors.New("unread and archived filters can't be combined")
+	}
+
+	return f, nil
+}
+
+// Validate checks configuration values, a problem is returned for each invalid field.
+// Empty values are valid, default ones are used instead.
+func (c WalgotConfig) Validate() []string {
+	var problems []string
+
+	positives := []struct {
+		name  string
+		value int
+	}{
+		{"NbEntriesPerAPICall", c.NbEntriesPerAPICall},
+		{"NbConcurrentAPICalls", c.NbConcurrentAPICalls},
+		{"NbAPIRetries", c.NbAPIRetries},
+		{"MaxOpenAtOnce", c.MaxOpenAtOnce},
+		{"ReadingWidth", c.ReadingWidth},
+		{"ListWidth", c.ListWidth},
+		{"PageJumpRows", c.PageJumpRows},
+	}
+	for _, p := range positives {
+		if p.value < 0 {
+			problems = append(problems, p.name+": must be positive (0 for default), got "+strconv.Itoa(p.value))
+		}
+	}
+	if c.NbEntriesPerAPICall > MaxNbEntriesPerAPICall {
+		problems = append(problems, "NbEntriesPerAPICall: must be at most "+strconv.Itoa(MaxNbEntriesPerAPICall)+", got "+strconv.Itoa(c.NbEntriesPerAPICall))
+	}
+	if c.CacheTTL < 0 {
+		problems = append(problems, "CacheTTL: must be positive, got "+c.CacheTTL.String())
+	}
+	if c.AutoRefreshInterval < 0 {
+		problems = append(problems, "AutoRefreshInterval: must be positive, got "+c.AutoRefreshInterval.String())
+	}
+	if c.APITimeout < 0 {
+		problems = append(problems, "APITimeout: must be positive, got "+c.APITimeout.String())
+	}
+	if len(c.DateFormat) > 0 && !IsValidDateFormat(c.DateFormat) {
+		problems = append(problems, "DateFormat: invalid go date layout "+c.DateFormat)
+	}
+	if !isOneOf(c.ContentRenderer, "", "text", "markdown") {
+		problems = append(problems, "ContentRenderer: must be \"text\" or \"markdown\", got "+c.ContentRenderer)
+	}
+	if !isOneOf(c.Appearance, "", "auto", "light", "dark") {
+		problems = append(problems, "Appearance: must be \"auto\", \"light\" or \"dark\", got "+c.Appearance)
+	}
+	if _, err := template.New("export").Parse(c.ExportPath); err != nil {
+		problems = append(problems, "ExportPath: invalid template: "+err.Error())
+	}
+	if !isOneOf(c.DefaultSortField, "", "created", "updated", "title", "reading") {
+		problems = append(problems, "DefaultSortField: must be \"created\", \"updated\", \"title\" or \"reading\", got "+c.DefaultSortField)
+	}
+	if !isOneOf(c.DefaultSortOrder, "", "asc", "desc") {
+		problems = append(problems, "DefaultSortOrder: must be \"asc\" or \"desc\", got "+c.DefaultSortOrder)
+	}
+	if !isOneOf(c.ListDensity, "", "comfortable", "compact") {
+		problems = append(problems, "ListDensity: must be \"comfortable\" or \"compact\", got "+c.ListDensity)
+	}
+	if !isOneOf(c.StartupView, "", "list", "grouped", "tags") {
+		problems = append(problems, "StartupView: must be \"list\", \"grouped\" or \"tags\", got "+c.StartupView)
+	}
+	if _, err := c.ParseStartupFilter(); err != nil {
+		problems = append(problems, "StartupFilter: "+err.Error())
+	}
+	for _, name := range c.ProfileNames() {
+		if !IsValidProfileName(name) {
+			problems = append(problems, "Profiles: invalid profile name \""+name+"\", only letters, digits, - and _ are allowed")
+		}
+		if strings.TrimSpace(c.Profiles[name]) == "" {
+			problems = append(problems, "Profiles: missing credentials file for profile "+name)
+		}
+	}
+	if _, err := c.ProfileCredentialsFile(c.Profile); err != nil {
+		problems = append(problems, "Profile: "+err.Error())
+	}
+
+	return problems
+}
+
+// ValidateCredentials checks the wallabag credentials file,
+// a problem is returned for each invalid field.
+func ValidateCredentials(credentialsJSON string) []string {
+	raw, err := ioutil.ReadFile(credentialsJSON)
+	if err != nil {
+		return []string{"CredentialsFile: " + err.Error()}
+	}
+
+	return validateCredentials(raw)
+}
+
+// Check wallabag credentials, a problem is returned for each invalid field.
+func validateCredentials(raw []byte) []string {
+	var credentials wallabago.WallabagConfig
+	raw = bytes.TrimPrefix(raw, []byte("\xef\xbb\xbf"))
+	if err := json.Unmarshal(raw, &credentials); err != nil {
+		return []string{"CredentialsFile: invalid JSON: " + err.Error()}
+	}
+
+	var problems []string
+	if u, err := url.Parse(credentials.WallabagURL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		problems = append(problems, "WallabagURL: must be a http(s) URL, got \""+credentials.WallabagURL+"\"")
+	}
+	required := []struct {
+		name  string
+		value string
+	}{
+		{"ClientId", credentials.ClientID},
+		{"ClientSecret", credentials.ClientSecret},
+		{"UserName", credentials.UserName},
+		{"UserPassword", credentials.UserPassword},
+	}
+	for _, r := range required {
+		if strings.TrimSpace(r.value) == "" {
+			problems = append(problems, r.name+": missing")
+		}
+	}
+
+	return problems
+}
+
+// Check if value is one of the given ones.
+func isOneOf(value string, values ...string) bool {
+	for _, v := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse a duration string, empty means 0.
+func parseDuration(d string) (time.Duration, error) {
+	if len(d) == 0 {
+		return 0, nil
+	}
+	return time.ParseDuration(d)
+}
+
 // Stolen from Wallabago code:
 // https://github.com/Strubbl/wallabago/blob/master/config.go#L40
 func getConfig(configJSON string) (config WalgotConfig, err error) {
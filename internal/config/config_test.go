@@ -1,7 +1,12 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
 )
 
 func TestReadJson(t *testing.T) {
@@ -76,3 +81,259 @@ func TestReadJson(t *testing.T) {
 		}
 	}
 }
+
+func TestExpandPath(t *testing.T) {
+	home, _ := homedir.Dir()
+	cwd, _ := os.Getwd()
+	t.Setenv("WALGOT_TEST_DIR", "/tmp/walgot")
+
+	var tests = []struct {
+		input            string
+		expectedPath     string
+		expectedIsErrNil bool
+	}{
+		{"/etc/walgot/walgot.json", "/etc/walgot/walgot.json", true},
+		{"~", home, true},
+		{"~/.config/walgot/walgot.json", filepath.Join(home, ".config/walgot/walgot.json"), true},
+		{"$HOME/.config/walgot/walgot.json", filepath.Join(home, ".config/walgot/walgot.json"), true},
+		{"$WALGOT_TEST_DIR/walgot.json", "/tmp/walgot/walgot.json", true},
+		{"walgot.json", filepath.Join(cwd, "walgot.json"), true},
+		{"./example/../walgot.json", filepath.Join(cwd, "walgot.json"), true},
+		{"~bacardi55/walgot.json", "", false},
+		{"", "", false},
+	}
+
+	for _, test := range tests {
+		p, e := ExpandPath(test.input)
+		if p != test.expectedPath {
+			t.Errorf("ExpandPath(%v): expectedPath %v, got %v", test.input, test.expectedPath, p)
+		}
+		isErrNil := (e == nil)
+		if isErrNil != test.expectedIsErrNil {
+			t.Errorf("ExpandPath(%v): expectedIsErrNil %v, got %v", test.input, test.expectedIsErrNil, isErrNil)
+		}
+	}
+}
+
+func TestReadJsonCacheTTL(t *testing.T) {
+	var tests = []struct {
+		input             string
+		expectedCacheFile string
+		expectedCacheTTL  time.Duration
+		expectedIsErrNil  bool
+	}{
+		{"{\"CacheFile\": \"~/.cache/walgot/cache.dat\", \"CacheTTL\": \"15m\"}", "~/.cache/walgot/cache.dat", 15 * time.Minute, true},
+		{"{\"CacheTTL\": \"1h30m\"}", "", 90 * time.Minute, true},
+		{"{\"CacheFile\": \"/tmp/walgot-cache.dat\"}", "/tmp/walgot-cache.dat", 0, true},
+		{"{\"CacheTTL\": \"15 minutes\"}", "", 0, false},
+	}
+
+	for _, test := range tests {
+		c, e := readJSON([]byte(test.input))
+		if c.CacheFile != test.expectedCacheFile {
+			t.Errorf("readJson(%v): expectedCacheFile %v, got %v", test.input, test.expectedCacheFile, c.CacheFile)
+		}
+		if c.CacheTTL != test.expectedCacheTTL {
+			t.Errorf("readJson(%v): expectedCacheTTL %v, got %v", test.input, test.expectedCacheTTL, c.CacheTTL)
+		}
+		isErrNil := (e == nil)
+		if isErrNil != test.expectedIsErrNil {
+			t.Errorf("readJson(%v): expectedIsErrNil %v, got %v", test.input, test.expectedIsErrNil, isErrNil)
+		}
+	}
+}
+
+func TestReadJsonAutoRefreshInterval(t *testing.T) {
+	var tests = []struct {
+		input                       string
+		expectedAutoRefreshInterval time.Duration
+		expectedIsErrNil            bool
+	}{
+		{"{\"AutoRefreshInterval\": \"10m\"}", 10 * time.Minute, true},
+		{"{\"AutoRefreshInterval\": \"0\"}", 0, true},
+		{"{}", 0, true},
+		{"{\"AutoRefreshInterval\": \"10 minutes\"}", 0, false},
+	}
+
+	for _, test := range tests {
+		c, e := readJSON([]byte(test.input))
+		if c.AutoRefreshInterval != test.expectedAutoRefreshInterval {
+			t.Errorf("readJson(%v): expectedAutoRefreshInterval %v, got %v", test.input, test.expectedAutoRefreshInterval, c.AutoRefreshInterval)
+		}
+		isErrNil := (e == nil)
+		if isErrNil != test.expectedIsErrNil {
+			t.Errorf("readJson(%v): expectedIsErrNil %v, got %v", test.input, test.expectedIsErrNil, isErrNil)
+		}
+	}
+}
+
+func TestIsValidDateFormat(t *testing.T) {
+	var tests = []struct {
+		input         string
+		expectedValid bool
+	}{
+		{"2006-01-02", true},
+		{"02/01/2006", true},
+		{"Jan 2, 2006", true},
+		{"2006-01-02 15:04", true},
+		{"", false},
+		{"  ", false},
+		{"YYYY-MM-DD", false},
+		{"date", false},
+	}
+
+	for _, test := range tests {
+		valid := IsValidDateFormat(test.input)
+		if valid != test.expectedValid {
+			t.Errorf("IsValidDateFormat(%v): expectedValid %v, got %v", test.input, test.expectedValid, valid)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	var tests = []struct {
+		input            WalgotConfig
+		expectedProblems int
+	}{
+		{WalgotConfig{}, 0},
+		{WalgotConfig{NbEntriesPerAPICall: 255, DateFormat: "02/01/2006", ContentRenderer: "markdown", Appearance: "dark", ExportPath: "~/notes/{{.Title}}.md"}, 0},
+		{WalgotConfig{NbEntriesPerAPICall: -1}, 1},
+		{WalgotConfig{NbEntriesPerAPICall: 300}, 0},
+		{WalgotConfig{NbEntriesPerAPICall: 555}, 1},
+		{WalgotConfig{NbConcurrentAPICalls: -1, ReadingWidth: -80, CacheTTL: -time.Minute}, 3},
+		{WalgotConfig{AutoRefreshInterval: 10 * time.Minute}, 0},
+		{WalgotConfig{AutoRefreshInterval: -time.Minute}, 1},
+		{WalgotConfig{DateFormat: "YYYY-MM-DD"}, 1},
+		{WalgotConfig{ContentRenderer: "html", Appearance: "blue"}, 2},
+		{WalgotConfig{ExportPath: "~/notes/{{.Title.md"}, 1},
+		{WalgotConfig{StartupView: "grouped", StartupFilter: "starred, tag:go"}, 0},
+		{WalgotConfig{StartupView: "detail", StartupFilter: "unread,archived"}, 2},
+		{WalgotConfig{DefaultSortField: "title", DefaultSortOrder: "asc"}, 0},
+		{WalgotConfig{DefaultSortField: "archived", DefaultSortOrder: "up"}, 2},
+		{WalgotConfig{ListDensity: "compact"}, 0},
+		{WalgotConfig{ListDensity: "dense"}, 1},
+		{WalgotConfig{Profile: "work", Profiles: map[string]string{"work": "~/work.json", "home": "~/home.json"}}, 0},
+		{WalgotConfig{Profile: "perso", Profiles: map[string]string{"my work": "~/work.json", "home": ""}}, 3},
+	}
+
+	for _, test := range tests {
+		problems := test.input.Validate()
+		if len(problems) != test.expectedProblems {
+			t.Errorf("Validate(%+v): expectedProblems %v, got %v", test.input, test.expectedProblems, problems)
+		}
+	}
+}
+
+func TestProfileCredentialsFile(t *testing.T) {
+	c := WalgotConfig{CredentialsFile: "credentials.json", Profiles: map[string]string{"work": "work.json"}}
+	var tests = []struct {
+		inputProfile     string
+		expected         string
+		expectedIsErrNil bool
+	}{
+		{"", "credentials.json", true},
+		{"work", "work.json", true},
+		{"home", "", false},
+	}
+
+	for _, test := range tests {
+		result, err := c.ProfileCredentialsFile(test.inputProfile)
+		if result != test.expected {
+			t.Errorf("ProfileCredentialsFile(%v): expected %v, got %v", test.inputProfile, test.expected, result)
+		}
+		if (err == nil) != test.expectedIsErrNil {
+			t.Errorf("ProfileCredentialsFile(%v): expectedIsErrNil %v, got %v", test.inputProfile, test.expectedIsErrNil, err)
+		}
+	}
+}
+
+func TestProfileFilePath(t *testing.T) {
+	var tests = []struct {
+		inputPath    string
+		inputProfile string
+		expected     string
+	}{
+		{"/tmp/walgot-cache.dat", "", "/tmp/walgot-cache.dat"},
+		{"/tmp/walgot-cache.dat", "work", "/tmp/walgot-cache-work.dat"},
+		{"/tmp/cache", "work", "/tmp/cache-work"},
+		{"/tmp/cache.d/cache", "home", "/tmp/cache.d/cache-home"},
+	}
+
+	for _, test := range tests {
+		if result := ProfileFilePath(test.inputPath, test.inputProfile); result != test.expected {
+			t.Errorf("ProfileFilePath(%v, %v): expected %v, got %v", test.inputPath, test.inputProfile, test.expected, result)
+		}
+	}
+}
+
+func TestParseStartupFilter(t *testing.T) {
+	var tests = []struct {
+		input            string
+		expectedFilters  StartupFilters
+		expectedIsErrNil bool
+	}{
+		{"", StartupFilters{}, true},
+		{"all", StartupFilters{}, true},
+		{"starred", StartupFilters{Starred: true}, true},
+		{"unread, public", StartupFilters{Unread: true, Public: true}, true},
+		{"archived,tag: golang ", StartupFilters{Archived: true, Tag: "golang"}, true},
+		{"search:wallabag api", StartupFilters{Search: "wallabag api"}, true},
+		{"unread,archived", StartupFilters{}, false},
+		{"tag:", StartupFilters{}, false},
+		{"favorites", StartupFilters{}, false},
+	}
+
+	for _, test := range tests {
+		f, e := WalgotConfig{StartupFilter: test.input}.ParseStartupFilter()
+		if f != test.expectedFilters {
+			t.Errorf("ParseStartupFilter(%v): expectedFilters %+v, got %+v", test.input, test.expectedFilters, f)
+		}
+		if (e == nil) != test.expectedIsErrNil {
+			t.Errorf("ParseStartupFilter(%v): expectedIsErrNil %v, got %v", test.input, test.expectedIsErrNil, e)
+		}
+	}
+}
+
+func TestValidateCredentials(t *testing.T) {
+	var tests = []struct {
+		input            string
+		expectedProblems int
+	}{
+		{"{\"WallabagURL\": \"https://wallabag.example.com\", \"ClientId\": \"id\", \"ClientSecret\": \"secret\", \"UserName\": \"user\", \"UserPassword\": \"password\"}", 0},
+		{"{\"WallabagURL\": \"wallabag.example.com\", \"ClientId\": \"id\", \"ClientSecret\": \"secret\", \"UserName\": \"user\", \"UserPassword\": \"password\"}", 1},
+		{"{\"WallabagURL\": \"https://wallabag.example.com\", \"ClientId\": \"id\"}", 3},
+		{"{}", 5},
+		{"{\"WallabagURL\": ", 1},
+	}
+
+	for _, test := range tests {
+		problems := validateCredentials([]byte(test.input))
+		if len(problems) != test.expectedProblems {
+			t.Errorf("validateCredentials(%v): expectedProblems %v, got %v", test.input, test.expectedProblems, problems)
+		}
+	}
+}
+
+func TestReadJsonAPITimeout(t *testing.T) {
+	var tests = []struct {
+		input              string
+		expectedAPITimeout time.Duration
+		expectedIsErrNil   bool
+	}{
+		{"{\"APITimeout\": \"30s\"}", 30 * time.Second, true},
+		{"{\"APITimeout\": \"1m\", \"CacheTTL\": \"15m\"}", time.Minute, true},
+		{"{}", 0, true},
+		{"{\"APITimeout\": \"30 seconds\"}", 0, false},
+	}
+
+	for _, test := range tests {
+		c, e := readJSON([]byte(test.input))
+		if c.APITimeout != test.expectedAPITimeout {
+			t.Errorf("readJson(%v): expectedAPITimeout %v, got %v", test.input, test.expectedAPITimeout, c.APITimeout)
+		}
+		isErrNil := (e == nil)
+		if isErrNil != test.expectedIsErrNil {
+			t.Errorf("readJson(%v): expectedIsErrNil %v, got %v", test.input, test.expectedIsErrNil, isErrNil)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package notify
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strconv"
+)
+
+// Send displays a desktop notification.
+// Relies on notify-send on linux, osascript on macOS.
+func Send(title, message string) error {
+	switch runtime.GOOS {
+	case "linux":
+		return exec.Command("notify-send", title, message).Run()
+	case "darwin":
+		script := "display notification " + strconv.Quote(message) + " with title " + strconv.Quote(title)
+		return exec.Command("osascript", "-e", script).Run()
+	}
+
+	return errors.New("unsupported platform")
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Strubbl/wallabago/v7"
+)
+
+// Cache format version, to be increased when the cache content changes
+// (eg: wallabago.Item update) so that older caches are ignored.
+const cacheVersion = 1
+
+// Header written before entries in cache file.
+type cacheHeader struct {
+	Version   int
+	CreatedAt time.Time
+}
+
+// Load entries from cache file.
+// Returns no entries (and no error) if there is no cache file, if it has been
+// written by another cache version or if the cache is older than the given ttl
+// (0 means no expiration).
+func loadEntriesFromCache(cacheFile string, ttl time.Duration) ([]wallabago.Item, error) {
+	entries := []wallabago.Item{}
+
+	info, err := os.Stat(cacheFile)
+	if err != nil {
+		// No cache file.
+		return entries, nil
+	}
+	if info.Mode()&1<<2 != 0 {
+		// https://stackoverflow.com/questions/45429210/how-do-i-check-a-files-permissions-in-linux-using-go
+		// other users have read permission
+		return entries, errors.New("cache file is readable by other users")
+	}
+
+	content, err := os.ReadFile(cacheFile)
+	if err != nil {
+		return entries, nil
+	}
+	decoder := gob.NewDecoder(bytes.NewBuffer(content))
+	// Cache without header or from another version is ignored:
+	var header cacheHeader
+	if err := decoder.Decode(&header); err != nil || header.Version != cacheVersion {
+		return entries, nil
+	}
+	// Stale cache is ignored:
+	if ttl > 0 && time.Since(header.CreatedAt) > ttl {
+		return entries, nil
+	}
+	if err := decoder.Decode(&entries); err != nil {
+		return []wallabago.Item{}, err
+	}
+
+	return entries, nil
+}
+
+// Remove cache file, if any.
+func removeCacheFile(cacheFile string) error {
+	if err := os.Remove(cacheFile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Save entries in cache file.
+func saveEntriesToCache(cacheFile string, entries []wallabago.Item) error {
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(cacheFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := gob.NewEncoder(file)
+	if err := encoder.Encode(cacheHeader{cacheVersion, time.Now()}); err != nil {
+		return err
+	}
+	return encoder.Encode(entries)
+}
@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Strubbl/wallabago/v7"
+)
+
+func TestEntriesCache(t *testing.T) {
+	dir := t.TempDir()
+	entries := []wallabago.Item{{ID: 1, Title: "one"}, {ID: 2, Title: "two"}}
+
+	// Write a cache file with the given header:
+	writeCache := func(name string, header interface{}) string {
+		cacheFile := filepath.Join(dir, name)
+		file, _ := os.OpenFile(cacheFile, os.O_CREATE|os.O_WRONLY, 0600)
+		defer file.Close()
+		encoder := gob.NewEncoder(file)
+		if header != nil {
+			encoder.Encode(header)
+		}
+		encoder.Encode(entries)
+		return cacheFile
+	}
+
+	saved := filepath.Join(dir, "saved.dat")
+	if err := saveEntriesToCache(saved, entries); err != nil {
+		t.Fatalf("saveEntriesToCache: unexpected error %v", err)
+	}
+
+	var tests = []struct {
+		inputCacheFile   string
+		inputTTL         time.Duration
+		expectedEntries  int
+		expectedIsErrNil bool
+	}{
+		{saved, 0, 2, true},
+		{saved, time.Hour, 2, true},
+		{filepath.Join(dir, "missing.dat"), 0, 0, true},
+		{writeCache("noheader.dat", nil), 0, 0, true},
+		{writeCache("oldversion.dat", cacheHeader{cacheVersion - 1, time.Now()}), 0, 0, true},
+		{writeCache("stale.dat", cacheHeader{cacheVersion, time.Now().Add(-2 * time.Hour)}), time.Hour, 0, true},
+		{writeCache("fresh.dat", cacheHeader{cacheVersion, time.Now().Add(-2 * time.Hour)}), 0, 2, true},
+	}
+
+	for _, test := range tests {
+		result, err := loadEntriesFromCache(test.inputCacheFile, test.inputTTL)
+		if len(result) != test.expectedEntries {
+			t.Errorf("loadEntriesFromCache(%v, %v): expectedEntries %v, got %v", test.inputCacheFile, test.inputTTL, test.expectedEntries, len(result))
+		}
+		isErrNil := (err == nil)
+		if isErrNil != test.expectedIsErrNil {
+			t.Errorf("loadEntriesFromCache(%v, %v): expectedIsErrNil %v, got %v", test.inputCacheFile, test.inputTTL, test.expectedIsErrNil, isErrNil)
+		}
+	}
+}
+
+func TestRemoveCacheFile(t *testing.T) {
+	cacheFile := filepath.Join(t.TempDir(), "cache.dat")
+	saveEntriesToCache(cacheFile, []wallabago.Item{{ID: 1}})
+
+	if err := removeCacheFile(cacheFile); err != nil {
+		t.Errorf("removeCacheFile(%v): unexpected error %v", cacheFile, err)
+	}
+	if _, err := os.Stat(cacheFile); !os.IsNotExist(err) {
+		t.Errorf("removeCacheFile(%v): cache file still exists", cacheFile)
+	}
+	// Missing cache file isn't an error:
+	if err := removeCacheFile(cacheFile); err != nil {
+		t.Errorf("removeCacheFile(%v): unexpected error %v for missing file", cacheFile, err)
+	}
+}
@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"strings"
+)
+
+// Title of list columns, by name in Columns option.
+var tableColumnTitles = map[string]string{
+	"id":       "ID",
+	"status":   "Status",
+	"starred":  "Starred",
+	"archived": "Archived",
+	"title":    "Title",
+	"excerpt":  "Excerpt",
+	"domain":   "Domain",
+	"tags":     "Tags",
+	"reading":  "Est. read",
+	"created":  "Created",
+	"updated":  "Updated",
+}
+
+// Return the columns displayed on wide screens, in order, and warnings for
+// invalid column names. Without configured columns, default ones are used, with
+// the tags and excerpt columns if enabled.
+// ID column is always first as it is used for selecting entries, title column
+// is always displayed as it takes the remaining space.
+func resolveTableColumns(custom []string, showTags bool, showExcerpt bool) ([]string, []string) {
+	if len(custom) == 0 {
+		columns := []string{"ID", "Status", "Title"}
+		if showExcerpt {
+			columns = append(columns, "Excerpt")
+		}
+		columns = append(columns, "Domain")
+		if showTags {
+			columns = append(columns, "Tags")
+		}
+		return append(columns, "Est. read", "Created"), nil
+	}
+
+	columns := []string{"ID"}
+	var warnings []string
+	seen := map[string]bool{"ID": true}
+	for _, name := range custom {
+		title, ok := tableColumnTitles[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			warnings = append(warnings, "unknown column: "+name)
+			continue
+		}
+		if seen[title] {
+			// ID is always first, it can be listed anyway:
+			if title != "ID" {
+				warnings = append(warnings, "duplicate column: "+name)
+			}
+			continue
+		}
+		seen[title] = true
+		columns = append(columns, title)
+	}
+	if !seen["Title"] {
+		warnings = append(warnings, "missing title column, it is displayed after ID")
+		columns = append([]string{"ID", "Title"}, columns[1:]...)
+	}
+
+	return columns, warnings
+}
+
+// Check if the given column is displayed.
+func hasTableColumn(columns []string, title string) bool {
+	for _, c := range columns {
+		if c == title {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Value of a flag column: the label if set, empty otherwise.
+func getFlagLabel(set bool, label string) string {
+	if set {
+		return label
+	}
+
+	return ""
+}
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestResolveTableColumns(t *testing.T) {
+	var tests = []struct {
+		inputCustom      []string
+		inputShowTags    bool
+		inputShowExcerpt bool
+		expected         []string
+		expectedWarnings int
+	}{
+		{nil, false, false, []string{"ID", "Status", "Title", "Domain", "Est. read", "Created"}, 0},
+		{nil, true, true, []string{"ID", "Status", "Title", "Excerpt", "Domain", "Tags", "Est. read", "Created"}, 0},
+		{[]string{"title", "updated", "reading"}, true, false, []string{"ID", "Title", "Updated", "Est. read"}, 0},
+		{[]string{"Starred", " archived ", "title", "id"}, false, false, []string{"ID", "Starred", "Archived", "Title"}, 0},
+		{[]string{"title", "unknown", "title"}, false, false, []string{"ID", "Title"}, 2},
+		{[]string{"domain", "created"}, false, false, []string{"ID", "Title", "Domain", "Created"}, 1},
+	}
+
+	for _, test := range tests {
+		columns, warnings := resolveTableColumns(test.inputCustom, test.inputShowTags, test.inputShowExcerpt)
+		if fmt.Sprint(columns) != fmt.Sprint(test.expected) {
+			t.Errorf("resolveTableColumns(%v, %v, %v): expected %v, got %v", test.inputCustom, test.inputShowTags, test.inputShowExcerpt, test.expected, columns)
+		}
+		if len(warnings) != test.expectedWarnings {
+			t.Errorf("resolveTableColumns(%v, %v, %v): expectedWarnings %v, got %v", test.inputCustom, test.inputShowTags, test.inputShowExcerpt, test.expectedWarnings, warnings)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package tui
+
+// Maximal number of converted articles kept in memory.
+const contentCacheSize = 50
+
+// Converted content of read articles, by entry ID, to display them again
+// without converting their HTML.
+// Contents are wrapped at the same width, they are forgotten when it changes.
+// When full, the article converted first is forgotten.
+type walgotContentCache struct {
+	Width    int
+	MaxSize  int
+	Contents map[int]string
+	// Entry IDs, in conversion order:
+	IDs []int
+}
+
+// Create an empty content cache, keeping at most maxSize articles.
+func newContentCache(maxSize int) *walgotContentCache {
+	return &walgotContentCache{MaxSize: maxSize, Contents: map[int]string{}}
+}
+
+// Retrieve the content of an entry wrapped at the given width, if cached.
+func (c *walgotContentCache) get(id, width int) (string, bool) {
+	if c == nil || width != c.Width {
+		return "", false
+	}
+	content, ok := c.Contents[id]
+
+	return content, ok
+}
+
+// Keep the content of an entry wrapped at the given width.
+func (c *walgotContentCache) set(id, width int, content string) {
+	if c == nil || c.MaxSize <= 0 {
+		return
+	}
+	if width != c.Width {
+		c.clear()
+		c.Width = width
+	}
+	if _, ok := c.Contents[id]; !ok {
+		if len(c.IDs) >= c.MaxSize {
+			delete(c.Contents, c.IDs[0])
+			c.IDs = c.IDs[1:]
+		}
+		c.IDs = append(c.IDs, id)
+	}
+	c.Contents[id] = content
+}
+
+// Forget the content of an entry, eg: when it is refreshed.
+func (c *walgotContentCache) remove(id int) {
+	if c == nil {
+		return
+	}
+	if _, ok := c.Contents[id]; !ok {
+		return
+	}
+	delete(c.Contents, id)
+	for i, cached := range c.IDs {
+		if cached == id {
+			c.IDs = append(c.IDs[:i], c.IDs[i+1:]...)
+			break
+		}
+	}
+}
+
+// Forget all contents, eg: when entries are reloaded.
+func (c *walgotContentCache) clear() {
+	if c == nil {
+		return
+	}
+	c.Contents = map[int]string{}
+	c.IDs = nil
+}
@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"testing"
+)
+
+func TestContentCache(t *testing.T) {
+	c := newContentCache(2)
+	c.set(1, 72, "one")
+	c.set(2, 72, "two")
+
+	if content, ok := c.get(1, 72); !ok || content != "one" {
+		t.Errorf("contentCache.get(1, 72): expected one, got %v (%v)", content, ok)
+	}
+	if _, ok := c.get(1, 80); ok {
+		t.Errorf("contentCache.get(1, 80): content with another width must not be returned")
+	}
+
+	// Oldest content is forgotten when full:
+	c.set(3, 72, "three")
+	if _, ok := c.get(1, 72); ok {
+		t.Errorf("contentCache.set(3, 72): oldest content must be forgotten")
+	}
+	if content, ok := c.get(3, 72); !ok || content != "three" {
+		t.Errorf("contentCache.get(3, 72): expected three, got %v (%v)", content, ok)
+	}
+
+	// Contents are forgotten when the width changes:
+	c.set(4, 80, "four")
+	if _, ok := c.get(2, 72); ok {
+		t.Errorf("contentCache.set(4, 80): contents of previous width must be forgotten")
+	}
+	if len(c.IDs) != 1 {
+		t.Errorf("contentCache.set(4, 80): expected 1 content, got %v", len(c.IDs))
+	}
+
+	c.set(5, 80, "five")
+	c.remove(4)
+	if _, ok := c.get(4, 80); ok {
+		t.Errorf("contentCache.remove(4): content must be forgotten")
+	}
+	if content, ok := c.get(5, 80); !ok || content != "five" || len(c.IDs) != 1 {
+		t.Errorf("contentCache.remove(4): expected only five, got %v (%v)", c.IDs, content)
+	}
+
+	c.clear()
+	if _, ok := c.get(5, 80); ok {
+		t.Errorf("contentCache.clear(): contents must be forgotten")
+	}
+
+	// A nil cache keeps nothing:
+	var n *walgotContentCache
+	n.set(1, 72, "one")
+	if _, ok := n.get(1, 72); ok {
+		t.Errorf("contentCache.get(1, 72) on nil cache: expected no content")
+	}
+}
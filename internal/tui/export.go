@@ -0,0 +1,236 @@
+package tui
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/Strubbl/wallabago/v7"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Maximum length of a title used in a file name.
+const exportTitleMaxLength = 100
+
+// Characters not allowed in file names on common file systems.
+var unsafeFilenameRegexp = regexp.MustCompile(`[/\\:*?"<>|\x00-\x1f]+`)
+
+// Data available in export path templates.
+type exportPathData struct {
+	ID     int
+	Title  string
+	Domain string
+}
+
+// Entry metadata exported as JSON, fields are named as in wallabag API.
+type exportEntryRecord struct {
+	ID          int      `json:"id"`
+	Title       string   `json:"title"`
+	URL         string   `json:"url"`
+	DomainName  string   `json:"domain_name"`
+	Tags        []string `json:"tags"`
+	IsArchived  bool     `json:"is_archived"`
+	IsStarred   bool     `json:"is_starred"`
+	IsPublic    bool     `json:"is_public"`
+	ReadingTime int      `json:"reading_time"`
+	CreatedAt   string   `json:"created_at,omitempty"`
+	UpdatedAt   string   `json:"updated_at,omitempty"`
+	ArchivedAt  string   `json:"archived_at,omitempty"`
+	StarredAt   string   `json:"starred_at,omitempty"`
+}
+
+// Convert an entry to markdown, with title, URL, tags and date as front matter.
+func getEntryMarkdown(entry *wallabago.Item) (string, error) {
+	converter := md.NewConverter("", true, nil)
+	content, err := converter.ConvertString(entry.Content)
+	if err != nil {
+		return "", err
+	}
+
+	var quotedTags []string
+	for _, t := range getEntryTagLabels(entry) {
+		quotedTags = append(quotedTags, strconv.Quote(t))
+	}
+
+	frontMatter := "---\n" +
+		"title: " + strconv.Quote(entry.Title) + "\n" +
+		"url: " + strconv.Quote(entry.URL) + "\n" +
+		"tags: [" + strings.Join(quotedTags, ", ") + "]\n"
+	if created := getEntryTime(entry.CreatedAt); !created.IsZero() {
+		frontMatter += "date: " + created.Format(time.RFC3339) + "\n"
+	}
+	frontMatter += "---\n\n"
+
+	return frontMatter + "# " + entry.Title + "\n\n" + content + "\n", nil
+}
+
+// Generate the export file path of an entry from the given template.
+// Eg: "/home/user/notes/{{.Title}}.md".
+func getExportPath(pathTemplate string, entry *wallabago.Item) (string, error) {
+	tmpl, err := template.New("export").Parse(pathTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	var path bytes.Buffer
+	data := exportPathData{
+		ID:     entry.ID,
+		Title:  sanitizeFilename(entry.Title),
+		Domain: sanitizeFilename(entry.DomainName),
+	}
+	if err := tmpl.Execute(&path, data); err != nil {
+		return "", err
+	}
+
+	return path.String(), nil
+}
+
+// Make a string safe to use as a file name.
+func sanitizeFilename(name string) string {
+	name = unsafeFilenameRegexp.ReplaceAllString(name, "-")
+	name = strings.Trim(strings.TrimSpace(name), ".")
+	if r := []rune(name); len(r) > exportTitleMaxLength {
+		name = strings.TrimSpace(string(r[:exportTitleMaxLength]))
+	}
+	if name == "" {
+		return "untitled"
+	}
+
+	return name
+}
+
+// Convert an entry to its exported metadata.
+func getEntryRecord(entry *wallabago.Item) exportEntryRecord {
+	formatTime := func(t *wallabago.WallabagTime) string {
+		if tt := getEntryTime(t); !tt.IsZero() {
+			return tt.Format(time.RFC3339)
+		}
+		return ""
+	}
+	tags := getEntryTagLabels(entry)
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return exportEntryRecord{
+		ID:          entry.ID,
+		Title:       entry.Title,
+		URL:         entry.URL,
+		DomainName:  entry.DomainName,
+		Tags:        tags,
+		IsArchived:  entry.IsArchived == 1,
+		IsStarred:   entry.IsStarred == 1,
+		IsPublic:    entry.IsPublic,
+		ReadingTime: entry.ReadingTime,
+		CreatedAt:   formatTime(entry.CreatedAt),
+		UpdatedAt:   formatTime(entry.UpdatedAt),
+		ArchivedAt:  formatTime(entry.ArchivedAt),
+		StarredAt:   formatTime(entry.StarredAt),
+	}
+}
+
+// Export entries metadata in a NDJSON file, one entry per line.
+func exportEntriesJSON(entries []wallabago.Item, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for i := range entries {
+		if err := encoder.Encode(getEntryRecord(&entries[i])); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// Add the entry ID to an export path if it is already used.
+// Eg: "notes/Title.md" becomes "notes/Title-42.md".
+func getUniqueExportPath(path string, id int, used map[string]bool) string {
+	if !used[path] {
+		return path
+	}
+	ext := filepath.Ext(path)
+
+	return strings.TrimSuffix(path, ext) + "-" + strconv.Itoa(id) + ext
+}
+
+// Export an entry as markdown, return the file path.
+func exportEntry(entry *wallabago.Item, pathTemplate string) (string, error) {
+	path, err := getExportPath(pathTemplate, entry)
+	if err != nil {
+		return "", err
+	}
+
+	return path, writeEntryExport(entry, path)
+}
+
+// Write an entry as markdown in the given file.
+func writeEntryExport(entry *wallabago.Item, path string) error {
+	content, err := getEntryMarkdown(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(content), 0600)
+}
+
+// Export entries as markdown files in the given directory.
+// File names are generated from fileTemplate, the entry ID is added
+// when several entries would be exported to the same file.
+// Messages are sent to the given channel, which is closed at the end.
+func exportEntries(messages chan tea.Msg, entries []wallabago.Item, directory, fileTemplate string) {
+	defer close(messages)
+
+	used := map[string]bool{}
+	exported, failed := 0, 0
+	for i := range entries {
+		path, err := getExportPath(fileTemplate, &entries[i])
+		if err == nil {
+			path = getUniqueExportPath(filepath.Join(directory, path), entries[i].ID, used)
+			used[path] = true
+			err = writeEntryExport(&entries[i], path)
+		}
+		if err != nil {
+			failed++
+			log.Println("Couldn't export entry", entries[i].ID, err)
+		} else {
+			exported++
+		}
+
+		messages <- walgotExportProgressMsg{
+			Done:     i + 1,
+			Total:    len(entries),
+			messages: messages,
+		}
+	}
+
+	messages <- walgotExportDoneMsg{
+		Exported:  exported,
+		Failed:    failed,
+		Directory: directory,
+	}
+}
@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Strubbl/wallabago/v7"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSanitizeFilename(t *testing.T) {
+	var tests = []struct {
+		inputName string
+		expected  string
+	}{
+		{"A title", "A title"},
+		{"Go 1.18: what's new?", "Go 1.18- what's new-"},
+		{"../../etc/passwd", "-..-etc-passwd"},
+		{"a/b\\c", "a-b-c"},
+		{"  ...  ", "untitled"},
+		{"", "untitled"},
+		{strings.Repeat("é", 150), strings.Repeat("é", exportTitleMaxLength)},
+	}
+
+	for _, test := range tests {
+		if result := sanitizeFilename(test.inputName); result != test.expected {
+			t.Errorf("sanitizeFilename(%v): expected %v, got %v", test.inputName, test.expected, result)
+		}
+	}
+}
+
+func TestGetExportPath(t *testing.T) {
+	entry := wallabago.Item{ID: 42, Title: "Hello/World", DomainName: "example.com"}
+	var tests = []struct {
+		inputTemplate string
+		expected      string
+		expectedError bool
+	}{
+		{"/notes/{{.Title}}.md", "/notes/Hello-World.md", false},
+		{"/notes/{{.Domain}}/{{.ID}}.md", "/notes/example.com/42.md", false},
+		{"/notes/{{.Title", "", true},
+		{"/notes/{{.Unknown}}.md", "", true},
+	}
+
+	for _, test := range tests {
+		result, err := getExportPath(test.inputTemplate, &entry)
+		if result != test.expected || (err != nil) != test.expectedError {
+			t.Errorf("getExportPath(%v): expected %v (error: %v), got %v (%v)", test.inputTemplate, test.expected, test.expectedError, result, err)
+		}
+	}
+}
+
+func TestExportEntry(t *testing.T) {
+	entry := wallabago.Item{
+		ID:        1,
+		Title:     "A \"quoted\" title",
+		URL:       "https://example.com/article",
+		Content:   "<p>Some <strong>content</strong></p>",
+		CreatedAt: &wallabago.WallabagTime{Time: time.Date(2021, time.November, 23, 20, 45, 30, 0, time.UTC)},
+		Tags:      []wallabago.Tag{{Label: "go"}, {Label: "tui"}},
+	}
+
+	path, err := exportEntry(&entry, filepath.Join(t.TempDir(), "notes", "{{.ID}}.md"))
+	if err != nil {
+		t.Fatalf("exportEntry: unexpected error %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("exportEntry: couldn't read exported file %v", err)
+	}
+
+	expected := "---\n" +
+		"title: \"A \\\"quoted\\\" title\"\n" +
+		"url: \"https://example.com/article\"\n" +
+		"tags: [\"go\", \"tui\"]\n" +
+		"date: 2021-11-23T20:45:30Z\n" +
+		"---\n\n" +
+		"# A \"quoted\" title\n\n" +
+		"Some **content**\n"
+	if string(raw) != expected {
+		t.Errorf("exportEntry: expected %q, got %q", expected, string(raw))
+	}
+}
+
+func TestGetUniqueExportPath(t *testing.T) {
+	used := map[string]bool{"/notes/Title.md": true}
+	var tests = []struct {
+		inputPath string
+		inputID   int
+		expected  string
+	}{
+		{"/notes/Other.md", 1, "/notes/Other.md"},
+		{"/notes/Title.md", 42, "/notes/Title-42.md"},
+	}
+
+	for _, test := range tests {
+		if result := getUniqueExportPath(test.inputPath, test.inputID, used); result != test.expected {
+			t.Errorf("getUniqueExportPath(%v, %v): expected %v, got %v", test.inputPath, test.inputID, test.expected, result)
+		}
+	}
+}
+
+func TestExportEntries(t *testing.T) {
+	directory := t.TempDir()
+	entries := []wallabago.Item{
+		{ID: 1, Title: "Same title"},
+		{ID: 2, Title: "Same title"},
+		{ID: 3, Title: "Other title"},
+	}
+
+	messages := make(chan tea.Msg)
+	go exportEntries(messages, entries, directory, "{{.Title}}.md")
+
+	var done walgotExportDoneMsg
+	for msg := range messages {
+		if d, ok := msg.(walgotExportDoneMsg); ok {
+			done = d
+		}
+	}
+	if done.Exported != 3 || done.Failed != 0 {
+		t.Errorf("exportEntries: expected 3 exported and 0 failed, got %v", done)
+	}
+
+	for _, name := range []string{"Same title.md", "Same title-2.md", "Other title.md"} {
+		if _, err := os.Stat(filepath.Join(directory, name)); err != nil {
+			t.Errorf("exportEntries: expected file %v, got %v", name, err)
+		}
+	}
+}
+
+func TestExportEntriesJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.ndjson")
+	entries := []wallabago.Item{
+		{
+			ID:          1,
+			Title:       "<Go> & TUI",
+			URL:         "https://example.com/1",
+			DomainName:  "example.com",
+			IsArchived:  1,
+			ReadingTime: 5,
+			CreatedAt:   &wallabago.WallabagTime{Time: time.Date(2021, time.November, 23, 20, 45, 30, 0, time.UTC)},
+			Tags:        []wallabago.Tag{{Label: "go"}},
+		},
+		{ID: 2, Title: "Second", IsStarred: 1, IsPublic: true},
+	}
+
+	if err := exportEntriesJSON(entries, path); err != nil {
+		t.Fatalf("exportEntriesJSON: unexpected error %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("exportEntriesJSON: couldn't read exported file %v", err)
+	}
+
+	expected := `{"id":1,"title":"<Go> & TUI","url":"https://example.com/1","domain_name":"example.com","tags":["go"],"is_archived":true,"is_starred":false,"is_public":false,"reading_time":5,"created_at":"2021-11-23T20:45:30Z"}` + "\n" +
+		`{"id":2,"title":"Second","url":"","domain_name":"","tags":[],"is_archived":false,"is_starred":true,"is_public":true,"reading_time":0}` + "\n"
+	if string(raw) != expected {
+		t.Errorf("exportEntriesJSON: expected %q, got %q", expected, string(raw))
+	}
+}
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/Strubbl/wallabago/v7"
+)
+
+// Label of entries without domain, in grouped by domain view.
+const noDomainLabel = "(no domain)"
+
+// A domain and its entries, in grouped by domain view.
+type walgotDomainGroup struct {
+	Domain  string
+	Entries []wallabago.Item
+}
+
+// A line of the grouped by domain view.
+// Domain headers have no ID, entries have the ID and title of the article.
+type walgotGroupedRow struct {
+	Domain   string
+	Count    int
+	Expanded bool
+	ID       int
+	Title    string
+}
+
+// Group entries matching filters by domain.
+// Domains with the most entries come first, entries keep their order.
+func getDomainGroups(entries []wallabago.Item, filters walgotTableFilters) []walgotDomainGroup {
+	groups := []walgotDomainGroup{}
+	indexes := map[string]int{}
+	for i := range entries {
+		if !isEntryMatchingFilters(&entries[i], filters) {
+			continue
+		}
+		domain := entries[i].DomainName
+		index, ok := indexes[domain]
+		if !ok {
+			index = len(groups)
+			indexes[domain] = index
+			groups = append(groups, walgotDomainGroup{Domain: domain})
+		}
+		groups[index].Entries = append(groups[index].Entries, entries[i])
+	}
+
+	sort.SliceStable(groups, func(a, b int) bool {
+		if len(groups[a].Entries) != len(groups[b].Entries) {
+			return len(groups[a].Entries) > len(groups[b].Entries)
+		}
+		return groups[a].Domain < groups[b].Domain
+	})
+
+	return groups
+}
+
+// Return the lines to display: a header per domain, followed by its entries if expanded.
+func getGroupedRows(groups []walgotDomainGroup, expanded map[string]bool) []walgotGroupedRow {
+	rows := []walgotGroupedRow{}
+	for _, g := range groups {
+		rows = append(rows, walgotGroupedRow{
+			Domain:   g.Domain,
+			Count:    len(g.Entries),
+			Expanded: expanded[g.Domain],
+		})
+		if !expanded[g.Domain] {
+			continue
+		}
+		for _, e := range g.Entries {
+			rows = append(rows, walgotGroupedRow{Domain: g.Domain, ID: e.ID, Title: e.Title})
+		}
+	}
+
+	return rows
+}
+
+// Return the position of the header of the given domain, -1 if not found.
+func getDomainRowPosition(rows []walgotGroupedRow, domain string) int {
+	for i, r := range rows {
+		if r.ID == 0 && r.Domain == domain {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Text of a line of the grouped by domain view.
+func (r walgotGroupedRow) text() string {
+	if r.ID > 0 {
+		return "    " + r.Title
+	}
+
+	domain := r.Domain
+	if domain == "" {
+		domain = noDomainLabel
+	}
+	icon := "▸ "
+	if r.Expanded {
+		icon = "▾ "
+	}
+
+	return icon + domain + " (" + strconv.Itoa(r.Count) + ")"
+}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Strubbl/wallabago/v7"
+)
+
+func TestGetDomainGroups(t *testing.T) {
+	entries := []wallabago.Item{
+		{ID: 1, DomainName: "b.org", IsArchived: 0},
+		{ID: 2, DomainName: "a.org", IsArchived: 0},
+		{ID: 3, DomainName: "c.org", IsArchived: 0},
+		{ID: 4, DomainName: "c.org", IsArchived: 1},
+		{ID: 5, DomainName: "c.org", IsArchived: 0},
+	}
+	var tests = []struct {
+		inputFilters    walgotTableFilters
+		expectedDomains []string
+		expectedIDs     [][]int
+	}{
+		{walgotTableFilters{}, []string{"c.org", "a.org", "b.org"}, [][]int{{3, 4, 5}, {2}, {1}}},
+		{walgotTableFilters{ReadState: "unread"}, []string{"c.org", "a.org", "b.org"}, [][]int{{3, 5}, {2}, {1}}},
+		{walgotTableFilters{ReadState: "archived"}, []string{"c.org"}, [][]int{{4}}},
+	}
+
+	for _, test := range tests {
+		groups := getDomainGroups(entries, test.inputFilters)
+		if len(groups) != len(test.expectedDomains) {
+			t.Errorf("getDomainGroups(%v): expected %v groups, got %v", test.inputFilters, len(test.expectedDomains), len(groups))
+			continue
+		}
+		for i, g := range groups {
+			if g.Domain != test.expectedDomains[i] {
+				t.Errorf("getDomainGroups(%v): expected domain %v at %v, got %v", test.inputFilters, test.expectedDomains[i], i, g.Domain)
+			}
+			ids := []int{}
+			for _, e := range g.Entries {
+				ids = append(ids, e.ID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(test.expectedIDs[i]) {
+				t.Errorf("getDomainGroups(%v): expected entries %v for %v, got %v", test.inputFilters, test.expectedIDs[i], g.Domain, ids)
+			}
+		}
+	}
+}
+
+func TestGetGroupedRows(t *testing.T) {
+	groups := []walgotDomainGroup{
+		{Domain: "a.org", Entries: []wallabago.Item{{ID: 1, Title: "One"}, {ID: 2, Title: "Two"}}},
+		{Domain: "", Entries: []wallabago.Item{{ID: 3, Title: "Three"}}},
+	}
+	var tests = []struct {
+		inputExpanded map[string]bool
+		expected      []string
+	}{
+		{nil, []string{"▸ a.org (2)", "▸ (no domain) (1)"}},
+		{map[string]bool{"a.org": true}, []string{"▾ a.org (2)", "    One", "    Two", "▸ (no domain) (1)"}},
+		{map[string]bool{"": true}, []string{"▸ a.org (2)", "▾ (no domain) (1)", "    Three"}},
+	}
+
+	for _, test := range tests {
+		rows := getGroupedRows(groups, test.inputExpanded)
+		texts := []string{}
+		for _, r := range rows {
+			texts = append(texts, r.text())
+		}
+		if fmt.Sprint(texts) != fmt.Sprint(test.expected) {
+			t.Errorf("getGroupedRows(%v): expected %v, got %v", test.inputExpanded, test.expected, texts)
+		}
+	}
+}
+
+func TestGetDomainRowPosition(t *testing.T) {
+	rows := []walgotGroupedRow{
+		{Domain: "a.org", Count: 1, Expanded: true},
+		{Domain: "a.org", ID: 1},
+		{Domain: "b.org", Count: 1},
+	}
+	var tests = []struct {
+		inputDomain string
+		expected    int
+	}{
+		{"a.org", 0},
+		{"b.org", 2},
+		{"c.org", -1},
+	}
+
+	for _, test := range tests {
+		if result := getDomainRowPosition(rows, test.inputDomain); result != test.expected {
+			t.Errorf("getDomainRowPosition(%v): expected %v, got %v", test.inputDomain, test.expected, result)
+		}
+	}
+}
@@ -0,0 +1,448 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Keybindings, action name -> key.
+type walgotKeys map[string]string
+
+// Default keybindings.
+var defaultKeybindings = walgotKeys{
+	"quit":             "q",
+	"help":             "?",
+	"reload":           "r",
+	"down":             "j",
+	"up":               "k",
+	"select":           "enter",
+	"filterUnread":     "u",
+	"filterStarred":    "s",
+	"filterArchived":   "a",
+	"filterPublic":     "p",
+	"cycleSort":        "c",
+	"toggleSortOrder":  "C",
+	"toggleArchive":    "A",
+	"toggleStar":       "S",
+	"togglePublic":     "P",
+	"open":             "O",
+	"openOriginal":     "o",
+	"copy":             "Y",
+	"copyOriginal":     "y",
+	"search":           "/",
+	"filterTag":        "t",
+	"wallabagSearch":   "f",
+	"add":              "n",
+	"delete":           "D",
+	"openLink":         "L",
+	"nextEntry":        "n",
+	"previousEntry":    "N",
+	"archiveAndNext":   "m",
+	"nextPage":         ">",
+	"previousPage":     "<",
+	"clearCache":       "X",
+	"toggleStatusLine": "i",
+	"export":           "E",
+	"exportAll":        "E",
+	"exportJSON":       "J",
+	"mark":             " ",
+	"openSelected":     "B",
+	"groupByDomain":    "d",
+	"tagsView":         "T",
+	"editTags":         "e",
+	"top":              "g",
+	"bottom":           "G",
+	"jump":             ":",
+	"cycleReadState":   "R",
+	"switchProfile":    "w",
+	"refreshEntry":     "r",
+	"logs":             "l",
+	"keysPopup":        ".",
+	"filterDate":       "F",
+	"randomEntry":      "v",
+	"toggleDensity":    "z",
+	"serverCount":      "#",
+	"searchArticle":    "/",
+	"nextMatch":        "n",
+	"previousMatch":    "N",
+	"toggleMetadata":   "M",
+}
+
+// Actions sharing a key on purpose, the action depends on the context
+// (eg: next match while searching in the article, otherwise next article).
+var sharedKeybindings = [][2]string{
+	{"nextEntry", "nextMatch"},
+	{"previousEntry", "previousMatch"},
+	{"export", "exportAll"},
+	{"reload", "refreshEntry"},
+}
+
+// Merge keybindings from configuration with default ones.
+// Unknown actions and empty keys are ignored, a warning is returned for each of them.
+// A warning is also returned for actions of a same view using the same key,
+// only one of them can be triggered.
+func resolveKeybindings(custom map[string]string) (walgotKeys, []string) {
+	keys := walgotKeys{}
+	for action, key := range defaultKeybindings {
+		keys[action] = key
+	}
+
+	var warnings []string
+	for action, key := range custom {
+		if _, ok := defaultKeybindings[action]; !ok {
+			warnings = append(warnings, "unknown keybinding action: "+action)
+			continue
+		}
+		if strings.TrimSpace(key) == "" {
+			warnings = append(warnings, "empty key for keybinding action: "+action)
+			continue
+		}
+		keys[action] = key
+	}
+	warnings = append(warnings, getKeybindingsConflicts(keys)...)
+	// Map iteration order is random, keep warnings stable:
+	sort.Strings(warnings)
+
+	return keys, warnings
+}
+
+// Return a warning for each key used by several actions of a same view,
+// actions available on all screens are checked in every view.
+func getKeybindingsConflicts(keys walgotKeys) []string {
+	var common []string
+	for _, group := range keybindingsHelp {
+		if group.Title == "On all screens" {
+			common = getHelpGroupActions(group)
+		}
+	}
+
+	var warnings []string
+	for _, group := range keybindingsHelp {
+		if group.Title == "On all screens" {
+			continue
+		}
+		actions := append(getHelpGroupActions(group), common...)
+		byKey := map[string]string{}
+		for _, action := range actions {
+			other, ok := byKey[keys[action]]
+			if !ok {
+				byKey[keys[action]] = action
+			} else if !isSharedKeybinding(other, action) {
+				warnings = append(warnings, fmt.Sprintf("key %q is used by both %s and %s %s", keys[action], other, action, strings.ToLower(group.Title)))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// Return the configurable actions of a help group, in order and without duplicates.
+func getHelpGroupActions(group walgotKeyHelpGroup) []string {
+	var actions []string
+	seen := map[string]bool{}
+	for _, entry := range group.Entries {
+		for _, action := range entry.Actions {
+			if !seen[action] {
+				seen[action] = true
+				actions = append(actions, action)
+			}
+		}
+	}
+
+	return actions
+}
+
+// Check if both actions share a key on purpose.
+func isSharedKeybinding(a, b string) bool {
+	for _, shared := range sharedKeybindings {
+		if shared == [2]string{a, b} || shared == [2]string{b, a} {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Return the entry field ("archive", "star" or "public") toggled by the given key.
+// In reading view, the public filter key also toggles public status.
+func getEntryUpdateField(key string, keys walgotKeys) string {
+	switch key {
+	case keys["toggleArchive"]:
+		return "archive"
+	case keys["toggleStar"]:
+		return "star"
+	case keys["togglePublic"], keys["filterPublic"]:
+		return "public"
+	}
+	return ""
+}
+
+// Check if a key triggers an action needing wallabag API, in list or detail view.
+func isNetworkAction(key string, keys walgotKeys, detailView bool) bool {
+	actions := []string{"reload", "clearCache", "toggleArchive", "toggleStar", "togglePublic", "delete", "add", "wallabagSearch", "nextPage", "previousPage", "editTags", "serverCount"}
+	if detailView {
+		actions = []string{"toggleArchive", "toggleStar", "togglePublic", "filterPublic", "delete", "archiveAndNext", "editTags", "refreshEntry"}
+	} else if key == "N" {
+		// Fixed alias for add:
+		return true
+	}
+
+	for _, action := range actions {
+		if key == keys[action] {
+			return true
+		}
+	}
+	return false
+}
+
+// Check if a key triggers an action that can be sent to wallabag later, in list or detail view.
+func isQueueableAction(key string, keys walgotKeys, detailView bool) bool {
+	actions := []string{"toggleArchive", "toggleStar", "delete"}
+	if detailView {
+		actions = append(actions, "archiveAndNext")
+	}
+
+	for _, action := range actions {
+		if key == keys[action] {
+			return true
+		}
+	}
+	return false
+}
+
+// Check if a key triggers an action updating entries on wallabag, in list or detail view.
+func isMutatingAction(key string, keys walgotKeys, detailView bool) bool {
+	actions := []string{"toggleArchive", "toggleStar", "togglePublic", "delete", "add", "editTags"}
+	if detailView {
+		actions = []string{"toggleArchive", "toggleStar", "togglePublic", "filterPublic", "delete", "archiveAndNext", "editTags"}
+	} else if key == "N" {
+		// Fixed alias for add:
+		return true
+	}
+
+	for _, action := range actions {
+		if key == keys[action] {
+			return true
+		}
+	}
+	return false
+}
+
+// Help entry for a keybinding.
+// Actions are configurable keybindings, Keys are fixed ones.
+type walgotKeyHelp struct {
+	Actions     []string
+	Keys        []string
+	Description string
+}
+
+// Help entries for a view.
+type walgotKeyHelpGroup struct {
+	Title   string
+	Entries []walgotKeyHelp
+}
+
+// Keybindings registry used to generate help.
+var keybindingsHelp = []walgotKeyHelpGroup{
+	{
+		Title: "On all screens",
+		Entries: []walgotKeyHelp{
+			{Keys: []string{"ctrl+c"}, Description: "Quit"},
+			{Actions: []string{"help"}, Description: "Help (this page)"},
+			{Actions: []string{"toggleStatusLine"}, Description: "Toggle status line, with wallabag server and user"},
+			{Actions: []string{"keysPopup"}, Description: "Display the keys of the current page in a popup, any key closes it"},
+			{Keys: []string{"esc"}, Description: "Cancel loading of entries, while reloading or refreshing in background"},
+		},
+	},
+	{
+		Title: "On listing page",
+		Entries: []walgotKeyHelp{
+			{Actions: []string{"reload"}, Description: "Reload article from wallabag via APIs, takes time depending on the number of articles saved"},
+			{Actions: []string{"filterUnread"}, Description: "Toggle display only unread articles (or all articles)"},
+			{Actions: []string{"filterStarred"}, Description: "Toggle display only starred articles"},
+			{Actions: []string{"filterArchived"}, Description: "Toggle display only archived articles (or all articles)"},
+			{Actions: []string{"cycleReadState"}, Description: "Cycle read state filter: unread, archived, all"},
+			{Actions: []string{"filterPublic"}, Description: "Toggle public only articles (articles with a public link)"},
+			{Actions: []string{"cycleSort"}, Description: "Cycle sort field (created, updated, title, reading time)"},
+			{Actions: []string{"toggleSortOrder"}, Description: "Toggle sort order (ascending / descending)"},
+			{Keys: []string{"click on header"}, Description: "Sort by Title, Est. read or Created column, click again to toggle sort order"},
+			{Actions: []string{"toggleArchive"}, Description: "Toggle Archive / Unread for the current article (and update wallabag backend)"},
+			{Actions: []string{"toggleStar"}, Description: "Toggle Starred / Unstarred for the current article (and update wallabag backend)"},
+			{Actions: []string{"togglePublic"}, Description: "Toggle Public status - Public means article can be shared with a public link"},
+			{Actions: []string{"open"}, Description: "Open article public link url in default browser. If article isn't public, it will open the original article link"},
+			{Actions: []string{"openOriginal"}, Description: "Open original article link in default browser"},
+			{Actions: []string{"copy"}, Description: "Yank (copy) URL to clipboard. If article isn't public, it will copy the original article link"},
+			{Actions: []string{"copyOriginal"}, Description: "Yank (copy) original article URL to clipboard"},
+			{Actions: []string{"search"}, Description: "Open search box, articles are filtered by title or domain while typing"},
+			{Actions: []string{"filterTag"}, Description: "Filter articles by tag"},
+			{Actions: []string{"filterDate"}, Description: "Filter articles by creation date, as a duration until now (eg: 7d, 2w, 3m, 1y) or a range of dates (eg: 2024-01-01..2024-02-01). Prefix with updated: to filter by update date"},
+			{Actions: []string{"editTags"}, Description: "Edit tags of the current article, as comma separated tags (and update wallabag backend)"},
+			{Actions: []string{"wallabagSearch"}, Description: "Search articles on wallabag server (esc or quit key to return to the full list)"},
+			{Actions: []string{"add"}, Keys: []string{"N"}, Description: "Add a new url to wallabag"},
+			{Actions: []string{"delete"}, Description: "Delete the selected entry, or all selected entries after confirmation"},
+			{Actions: []string{"mark"}, Description: "Select / unselect entry for batch actions: archive, star, public and delete apply to all selected entries"},
+			{Actions: []string{"openSelected"}, Description: "Open original links of all selected entries in default browser (after confirmation above MaxOpenAtOnce links)"},
+			{Actions: []string{"exportAll"}, Description: "Export all articles matching current filters as markdown files, in the given directory"},
+			{Actions: []string{"exportJSON"}, Description: "Export metadata (ID, title, URL, tags, status and dates) of articles matching current filters to a NDJSON file, one article per line"},
+			{Actions: []string{"clearCache"}, Description: "Remove cache file and reload all entries from wallabag (after confirmation)"},
+			{Actions: []string{"groupByDomain"}, Description: "Browse articles grouped by domain"},
+			{Actions: []string{"tagsView"}, Description: "List tags of loaded articles, with the number of articles per tag"},
+			{Actions: []string{"switchProfile"}, Description: "Switch to another wallabag account, as configured in Profiles option"},
+			{Actions: []string{"logs"}, Description: "Display the last log lines, to diagnose errors without leaving walgot"},
+			{Actions: []string{"serverCount"}, Description: "Check the number of articles on wallabag without reloading them, new ones are shown in the footer"},
+			{Keys: []string{"esc"}, Description: "Clear selected entries if any, then clean search, tag, date and wallabag search filters"},
+			{Actions: []string{"up"}, Keys: []string{"↑"}, Description: "Move up one item in the list"},
+			{Actions: []string{"down"}, Keys: []string{"↓"}, Description: "Move down one item in the list"},
+			{Keys: []string{"page up", "page down"}, Description: "Move up / down a page (or PageJumpRows items)"},
+			{Keys: []string{"ctrl+u", "ctrl+d"}, Description: "Move up / down half a page"},
+			{Keys: []string{"mouse wheel"}, Description: "Move up / down one item in the list"},
+			{Actions: []string{"top"}, Keys: []string{"home"}, Description: "Go to the top of the list (top key is pressed twice, eg: gg)"},
+			{Actions: []string{"bottom"}, Keys: []string{"end"}, Description: "Go to bottom of the list"},
+			{Actions: []string{"jump"}, Description: "Go to a line number, or to an entry ID prefixed with # (eg: #42)"},
+			{Actions: []string{"nextPage"}, Description: "Load next page of articles (pagination mode only)"},
+			{Actions: []string{"previousPage"}, Description: "Load previous page of articles (pagination mode only)"},
+			{Actions: []string{"select"}, Description: "Select entry to read content"},
+			{Actions: []string{"randomEntry"}, Description: "Read a random article of the list, unread articles first"},
+			{Actions: []string{"toggleDensity"}, Description: "Toggle compact list (more rows, no styling) / comfortable list"},
+			{Actions: []string{"quit"}, Description: "Remove search, tag, date and wallabag search filters if any, otherwise quit"},
+		},
+	},
+	{
+		Title: "On detail page",
+		Entries: []walgotKeyHelp{
+			{Actions: []string{"toggleArchive"}, Description: "Toggle Archive / Unread for the current article (and update wallabag backend)"},
+			{Actions: []string{"toggleStar"}, Description: "Toggle Starred / Unstarred for the current article (and update wallabag backend)"},
+			{Actions: []string{"togglePublic", "filterPublic"}, Description: "Toggle Public status - Public means article can be shared with a public link"},
+			{Actions: []string{"open"}, Description: "Open article public link url in default browser. If article isn't public, it will open the original article link"},
+			{Actions: []string{"openOriginal"}, Description: "Open original article link in default browser"},
+			{Actions: []string{"copy"}, Description: "Yank (copy) URL to clipboard. If article isn't public, it will copy the original article link"},
+			{Actions: []string{"copyOriginal"}, Description: "Yank (copy) original article URL to clipboard"},
+			{Actions: []string{"openLink"}, Description: "Open link within content. Give a link number as displayed in footnotes of the article"},
+			{Actions: []string{"export"}, Description: "Export article as markdown, with title, URL, tags and date as front matter (see ExportPath option)"},
+			{Actions: []string{"editTags"}, Description: "Edit tags of the article, as comma separated tags (and update wallabag backend)"},
+			{Actions: []string{"refreshEntry"}, Description: "Refresh the article from wallabag, eg: after editing it on another device"},
+			{Actions: []string{"delete"}, Description: "Delete the selected entry"},
+			{Actions: []string{"nextEntry"}, Description: "Read next article of the list"},
+			{Actions: []string{"previousEntry"}, Description: "Read previous article of the list"},
+			{Actions: []string{"archiveAndNext"}, Description: "Mark as read (archive) and read next article of the list, or return to the list if it was the last one"},
+			{Actions: []string{"searchArticle"}, Description: "Search in the article, matches are highlighted (empty search to clear it)"},
+			{Actions: []string{"nextMatch", "previousMatch"}, Description: "Go to next / previous match of the search in the article, instead of next / previous article"},
+			{Keys: []string{"esc"}, Description: "Clear the search in the article"},
+			{Actions: []string{"toggleMetadata"}, Description: "Toggle metadata panel above the article: URL, domain, dates, reading time, tags and status"},
+			{Actions: []string{"up"}, Keys: []string{"↑"}, Description: "Go up"},
+			{Actions: []string{"down"}, Keys: []string{"↓"}, Description: "Go down"},
+			{Keys: []string{"page up", "page down", "ctrl+u", "ctrl+d"}, Description: "Go up / down half a page"},
+			{Keys: []string{"mouse wheel"}, Description: "Scroll up / down"},
+			{Actions: []string{"top"}, Keys: []string{"home"}, Description: "Go to the top of the article (top key is pressed twice, eg: gg)"},
+			{Actions: []string{"bottom"}, Keys: []string{"end"}, Description: "Go to the bottom of the article"},
+			{Actions: []string{"quit"}, Description: "Return to list"},
+		},
+	},
+	{
+		Title: "On grouped by domain page",
+		Entries: []walgotKeyHelp{
+			{Actions: []string{"select"}, Description: "Expand / collapse the selected domain, or read the selected article"},
+			{Keys: []string{"→", "←"}, Description: "Expand / collapse the domain of the selected line"},
+			{Actions: []string{"up"}, Keys: []string{"↑"}, Description: "Move up one line"},
+			{Actions: []string{"down"}, Keys: []string{"↓"}, Description: "Move down one line"},
+			{Keys: []string{"page up", "page down"}, Description: "Move up / down a page (or PageJumpRows lines)"},
+			{Keys: []string{"ctrl+u", "ctrl+d"}, Description: "Move up / down half a page"},
+			{Actions: []string{"top"}, Keys: []string{"home"}, Description: "Go to the first line (top key is pressed twice, eg: gg)"},
+			{Actions: []string{"bottom"}, Keys: []string{"end"}, Description: "Go to the last line"},
+			{Actions: []string{"reload"}, Description: "Reload article from wallabag via APIs"},
+			{Actions: []string{"tagsView"}, Description: "List tags of loaded articles"},
+			{Actions: []string{"logs"}, Description: "Display the last log lines"},
+			{Actions: []string{"groupByDomain", "quit"}, Keys: []string{"esc"}, Description: "Return to list"},
+		},
+	},
+	{
+		Title: "On tags page",
+		Entries: []walgotKeyHelp{
+			{Actions: []string{"select"}, Description: "Filter articles by the selected tag"},
+			{Actions: []string{"up"}, Keys: []string{"↑"}, Description: "Move up one tag"},
+			{Actions: []string{"down"}, Keys: []string{"↓"}, Description: "Move down one tag"},
+			{Keys: []string{"page up", "page down"}, Description: "Move up / down a page (or PageJumpRows tags)"},
+			{Keys: []string{"ctrl+u", "ctrl+d"}, Description: "Move up / down half a page"},
+			{Actions: []string{"top"}, Keys: []string{"home"}, Description: "Go to the first tag (top key is pressed twice, eg: gg)"},
+			{Actions: []string{"bottom"}, Keys: []string{"end"}, Description: "Go to the last tag"},
+			{Actions: []string{"tagsView", "quit"}, Keys: []string{"esc"}, Description: "Return to articles"},
+		},
+	},
+	{
+		Title: "On profiles page",
+		Entries: []walgotKeyHelp{
+			{Actions: []string{"select"}, Description: "Switch to the selected profile, its articles are loaded"},
+			{Actions: []string{"up"}, Keys: []string{"↑"}, Description: "Move up one profile"},
+			{Actions: []string{"down"}, Keys: []string{"↓"}, Description: "Move down one profile"},
+			{Keys: []string{"home"}, Description: "Go to the first profile"},
+			{Actions: []string{"bottom"}, Keys: []string{"end"}, Description: "Go to the last profile"},
+			{Actions: []string{"switchProfile", "quit"}, Keys: []string{"esc"}, Description: "Return to articles"},
+		},
+	},
+	{
+		Title: "On logs page",
+		Entries: []walgotKeyHelp{
+			{Actions: []string{"up"}, Keys: []string{"↑"}, Description: "Go up"},
+			{Actions: []string{"down"}, Keys: []string{"↓"}, Description: "Go down"},
+			{Keys: []string{"page up", "page down", "ctrl+u", "ctrl+d"}, Description: "Go up / down half a page"},
+			{Actions: []string{"top"}, Keys: []string{"home"}, Description: "Go to the oldest line (top key is pressed twice, eg: gg)"},
+			{Actions: []string{"bottom"}, Keys: []string{"end"}, Description: "Go to the newest line"},
+			{Actions: []string{"reload"}, Description: "Display lines logged since the page has been opened"},
+			{Actions: []string{"logs", "quit"}, Keys: []string{"esc"}, Description: "Return to articles"},
+		},
+	},
+	{
+		Title: "On help page",
+		Entries: []walgotKeyHelp{
+			{Actions: []string{"quit"}, Keys: []string{"esc"}, Description: "Return to list"},
+		},
+	},
+	{
+		Title: "On any dialog (modal) view",
+		Entries: []walgotKeyHelp{
+			{Keys: []string{"enter"}, Description: "Validate (keep search filter on search dialog)"},
+			{Keys: []string{"esc"}, Description: "Close the dialog (remove search filter on search dialog)"},
+		},
+	},
+}
+
+// Help group of each view, displayed in the keys popup.
+var keybindingsHelpViews = map[string]string{
+	"list":     "On listing page",
+	"detail":   "On detail page",
+	"grouped":  "On grouped by domain page",
+	"tags":     "On tags page",
+	"profiles": "On profiles page",
+	"logs":     "On logs page",
+}
+
+// Return the help entries of the given view, as "keys: description" lines.
+func getViewKeysHelp(view string, keys walgotKeys) []string {
+	var lines []string
+	for _, group := range keybindingsHelp {
+		if group.Title != keybindingsHelpViews[view] {
+			continue
+		}
+		for _, entry := range group.Entries {
+			lines = append(lines, strings.Join(entry.getKeys(keys), ", ")+": "+entry.Description)
+		}
+	}
+
+	return lines
+}
+
+// Return the keys of a help entry, configured ones first.
+func (h walgotKeyHelp) getKeys(keys walgotKeys) []string {
+	var k []string
+	for _, action := range h.Actions {
+		// Space key can't be read otherwise:
+		if keys[action] == " " {
+			k = append(k, "space")
+			continue
+		}
+		k = append(k, keys[action])
+	}
+
+	return append(k, h.Keys...)
+}
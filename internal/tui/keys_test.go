@@ -0,0 +1,185 @@
+package tui
+
+import (
+	"testing"
+)
+
+func TestResolveKeybindings(t *testing.T) {
+	var tests = []struct {
+		inputCustom      map[string]string
+		action           string
+		expectedKey      string
+		expectedWarnings int
+	}{
+		{nil, "reload", "r", 0},
+		{map[string]string{"reload": "Z"}, "reload", "Z", 0},
+		{map[string]string{"quit": "Q"}, "reload", "r", 0},
+		{map[string]string{"quit": "Q"}, "quit", "Q", 0},
+		{map[string]string{"unknown": "x"}, "reload", "r", 1},
+		{map[string]string{"reload": ""}, "reload", "r", 1},
+		{map[string]string{"reload": "Z", "foo": "x", "bar": "y"}, "reload", "Z", 2},
+		// Same key as another action of the view, only one can be triggered:
+		{map[string]string{"reload": "R"}, "reload", "R", 1},
+		{map[string]string{"tagsView": "r"}, "tagsView", "r", 2},
+		{map[string]string{"help": "q"}, "help", "q", 7},
+		// Keys shared on purpose, or in different views:
+		{map[string]string{"nextMatch": "x", "nextEntry": "x"}, "nextMatch", "x", 0},
+		{map[string]string{"refreshEntry": "T"}, "refreshEntry", "T", 0},
+	}
+
+	for _, test := range tests {
+		keys, warnings := resolveKeybindings(test.inputCustom)
+		if keys[test.action] != test.expectedKey {
+			t.Errorf("resolveKeybindings(%v): expectedKey for %v %v, got %v", test.inputCustom, test.action, test.expectedKey, keys[test.action])
+		}
+		if len(warnings) != test.expectedWarnings {
+			t.Errorf("resolveKeybindings(%v): expectedWarnings %v, got %v", test.inputCustom, test.expectedWarnings, warnings)
+		}
+	}
+
+	// Defaults must not be modified by custom keybindings:
+	if defaultKeybindings["reload"] != "r" {
+		t.Errorf("resolveKeybindings: default keybindings have been modified")
+	}
+}
+
+func TestGetKeybindingsConflicts(t *testing.T) {
+	keys, _ := resolveKeybindings(map[string]string{"tagsView": "r"})
+	warnings := getKeybindingsConflicts(keys)
+	expected := `key "r" is used by both reload and tagsView on listing page`
+	if len(warnings) == 0 || warnings[0] != expected {
+		t.Errorf("getKeybindingsConflicts(tagsView: r): expected first warning %v, got %v", expected, warnings)
+	}
+}
+
+func TestKeybindingsHelp(t *testing.T) {
+	documented := map[string]bool{}
+	for _, group := range keybindingsHelp {
+		for _, entry := range group.Entries {
+			for _, action := range entry.Actions {
+				if _, ok := defaultKeybindings[action]; !ok {
+					t.Errorf("keybindingsHelp: unknown action %v in %v", action, group.Title)
+				}
+				documented[action] = true
+			}
+		}
+	}
+
+	for action := range defaultKeybindings {
+		if !documented[action] {
+			t.Errorf("keybindingsHelp: action %v is not documented", action)
+		}
+	}
+}
+
+func TestGetViewKeysHelp(t *testing.T) {
+	var tests = []struct {
+		inputView     string
+		expectedFirst string
+	}{
+		{"list", "r: Reload article from wallabag via APIs, takes time depending on the number of articles saved"},
+		{"profiles", "enter: Switch to the selected profile, its articles are loaded"},
+		{"help", ""},
+	}
+
+	for _, test := range tests {
+		lines := getViewKeysHelp(test.inputView, defaultKeybindings)
+		first := ""
+		if len(lines) > 0 {
+			first = lines[0]
+		}
+		if first != test.expectedFirst {
+			t.Errorf("getViewKeysHelp(%v): expected first line %v, got %v", test.inputView, test.expectedFirst, first)
+		}
+	}
+
+	// All views with keys have help entries:
+	for view := range keybindingsHelpViews {
+		if len(getViewKeysHelp(view, defaultKeybindings)) == 0 {
+			t.Errorf("getViewKeysHelp(%v): no help entries", view)
+		}
+	}
+}
+
+func TestIsNetworkAction(t *testing.T) {
+	var tests = []struct {
+		inputKey        string
+		inputDetailView bool
+		expected        bool
+	}{
+		{"r", false, true},
+		{"A", false, true},
+		{"A", true, true},
+		{"n", false, true},
+		{"N", false, true},
+		{"n", true, false},
+		{"N", true, false},
+		{"m", true, true},
+		{"p", false, false},
+		{"p", true, true},
+		{"j", false, false},
+		{"e", false, true},
+		{"e", true, true},
+		{"/", false, false},
+		{"r", true, true},
+		{"#", false, true},
+		{"#", true, false},
+	}
+
+	for _, test := range tests {
+		if result := isNetworkAction(test.inputKey, defaultKeybindings, test.inputDetailView); result != test.expected {
+			t.Errorf("isNetworkAction(%v, %v): expected %v, got %v", test.inputKey, test.inputDetailView, test.expected, result)
+		}
+	}
+}
+
+func TestIsQueueableAction(t *testing.T) {
+	var tests = []struct {
+		inputKey        string
+		inputDetailView bool
+		expected        bool
+	}{
+		{"A", false, true},
+		{"S", true, true},
+		{"D", false, true},
+		{"m", true, true},
+		{"m", false, false},
+		{"P", false, false},
+		{"e", false, false},
+		{"r", false, false},
+	}
+
+	for _, test := range tests {
+		if result := isQueueableAction(test.inputKey, defaultKeybindings, test.inputDetailView); result != test.expected {
+			t.Errorf("isQueueableAction(%v, %v): expected %v, got %v", test.inputKey, test.inputDetailView, test.expected, result)
+		}
+	}
+}
+
+func TestIsMutatingAction(t *testing.T) {
+	var tests = []struct {
+		inputKey        string
+		inputDetailView bool
+		expected        bool
+	}{
+		{"A", false, true},
+		{"A", true, true},
+		{"n", false, true},
+		{"N", false, true},
+		{"n", true, false},
+		{"m", true, true},
+		{"p", false, false},
+		{"p", true, true},
+		{"e", false, true},
+		{"D", true, true},
+		{"r", false, false},
+		{"r", true, false},
+		{"f", false, false},
+	}
+
+	for _, test := range tests {
+		if result := isMutatingAction(test.inputKey, defaultKeybindings, test.inputDetailView); result != test.expected {
+			t.Errorf("isMutatingAction(%v, %v): expected %v, got %v", test.inputKey, test.inputDetailView, test.expected, result)
+		}
+	}
+}
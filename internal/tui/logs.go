@@ -0,0 +1,64 @@
+package tui
+
+import (
+	"strings"
+	"sync"
+)
+
+// Number of log lines kept in memory, displayed in the logs view.
+const logBufferSize = 200
+
+// LogBuffer keeps the last log lines, to display them in the logs view.
+// It is used as log output (with the log file), logs can be written
+// by API calls at any time.
+type LogBuffer struct {
+	mutex sync.Mutex
+	lines []string
+	// Position of the next line, the oldest one once the buffer is full:
+	next int
+	full bool
+}
+
+// NewLogBuffer creates an empty buffer, keeping the last logBufferSize lines.
+func NewLogBuffer() *LogBuffer {
+	return newLogBufferWithSize(logBufferSize)
+}
+
+// Create an empty buffer, keeping the last size lines.
+func newLogBufferWithSize(size int) *LogBuffer {
+	return &LogBuffer{lines: make([]string, size)}
+}
+
+// Write adds log lines to the buffer, the oldest ones are dropped when full.
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if len(b.lines) == 0 {
+		return len(p), nil
+	}
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		b.lines[b.next] = line
+		b.next = (b.next + 1) % len(b.lines)
+		if b.next == 0 {
+			b.full = true
+		}
+	}
+
+	return len(p), nil
+}
+
+// Return the buffered lines, from the oldest to the newest.
+func (b *LogBuffer) getLines() []string {
+	if b == nil {
+		return nil
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.full {
+		return append([]string{}, b.lines[:b.next]...)
+	}
+
+	return append(append([]string{}, b.lines[b.next:]...), b.lines[:b.next]...)
+}
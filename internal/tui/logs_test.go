@@ -0,0 +1,32 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLogBuffer(t *testing.T) {
+	var tests = []struct {
+		inputWrites []string
+		expected    []string
+	}{
+		{nil, []string{}},
+		{[]string{"one\n"}, []string{"one"}},
+		{[]string{"one\n", "two\nthree\n"}, []string{"one", "two", "three"}},
+		// Oldest lines are dropped:
+		{[]string{"one\n", "two\n", "three\n", "four\n"}, []string{"two", "three", "four"}},
+		{[]string{"one\ntwo\nthree\nfour\nfive\n"}, []string{"three", "four", "five"}},
+	}
+
+	for _, test := range tests {
+		b := newLogBufferWithSize(3)
+		for _, w := range test.inputWrites {
+			if n, err := b.Write([]byte(w)); err != nil || n != len(w) {
+				t.Errorf("LogBuffer.Write(%q): expected %v written, got %v (%v)", w, len(w), n, err)
+			}
+		}
+		if lines := b.getLines(); fmt.Sprint(lines) != fmt.Sprint(test.expected) {
+			t.Errorf("LogBuffer.getLines() after %q: expected %v, got %v", test.inputWrites, test.expected, lines)
+		}
+	}
+}
@@ -1,14 +1,23 @@
 package tui
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"git.bacardi55.io/bacardi55/walgot/internal/api"
+	"git.bacardi55.io/bacardi55/walgot/internal/config"
+
 	"github.com/Strubbl/wallabago/v7"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // Manage update messages on the help view.
@@ -16,8 +25,8 @@ func updateHelpView(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "q", "esc":
-			m.CurrentView = "list"
+		case m.Keys["quit"], "esc":
+			m.CurrentView = m.BrowsingView
 		}
 	}
 	return m, nil
@@ -32,46 +41,156 @@ func updateEntryView(msg tea.Msg, m *model) (tea.Model, tea.Cmd) {
 	// A row has been selected, display article detail:
 	case walgotSelectRowMsg:
 		m.CurrentView = "detail"
-		m.Viewport.SetContent(getDetailViewportContent(m.SelectedID, m.Entries, m.TermSize.Width))
+		m.ArticleSearch = walgotArticleSearch{}
+		setDetailViewportContent(m)
+		// Resume reading where it was left:
+		m.Viewport.SetYOffset(m.ScrollPositions[m.SelectedID])
+		// Annotations may have changed since entries were loaded:
+		if !m.Offline {
+			return m, requestWallabagAnnotations(m.Ctx, m.SelectedID, m.DebugMode)
+		}
 
 	case tea.KeyMsg:
+		// During a search in the article, next / previous keys go to matches:
+		if m.ArticleSearch.Term != "" {
+			switch msg.String() {
+			case m.Keys["nextMatch"]:
+				jumpToMatch(m, 1)
+				return m, nil
+			case m.Keys["previousMatch"]:
+				jumpToMatch(m, -1)
+				return m, nil
+			case "esc":
+				m.ArticleSearch = walgotArticleSearch{}
+				refreshDetailViewport(m)
+				return m, nil
+			}
+		}
+
 		switch msg.String() {
-		case "q":
-			m.CurrentView = "list"
+		case m.Keys["quit"]:
+			saveScrollPosition(m)
+			m.CurrentView = m.BrowsingView
+			m.ArticleSearch = walgotArticleSearch{}
 			// Reset selection.
 			m.SelectedID = 0
 			// Make sure to scrollback up for other articles:
 			m.Viewport.GotoTop()
-		case "j", "down":
+		case m.Keys["down"], "down":
 			m.Viewport.LineDown(1)
-		case "k", "up":
+		case m.Keys["up"], "up":
 			m.Viewport.LineUp(1)
-		case "pagedown":
+		case "pgdown", "ctrl+d":
 			m.Viewport.HalfViewDown()
-		case "pageup":
+		case "pgup", "ctrl+u":
 			m.Viewport.HalfViewUp()
-		case "alt+[H":
+		case m.Keys["top"]:
+			// Top key needs to be pressed twice (eg: "gg"):
+			if top, cmd := isDoubleKeyPress(m, msg.String()); !top {
+				return m, cmd
+			}
+			m.Viewport.GotoTop()
+		case "home":
 			m.Viewport.GotoTop()
-		case "alt+[F":
+		case m.Keys["bottom"], "end":
 			m.Viewport.GotoBottom()
 
-		// Update article (archive, starred, public):
-		case "A", "S", "P":
+		// Update article (archive, starred, public).
+		// Public filter key toggles public status in reading view:
+		case m.Keys["toggleArchive"], m.Keys["toggleStar"], m.Keys["togglePublic"], m.Keys["filterPublic"]:
 			sID := m.SelectedID
-			a, s, p, action := sendEntryUpdate(msg.String(), m.SelectedID, m)
+			a, s, p, action, err := sendEntryUpdate(getEntryUpdateField(msg.String(), m.Keys), m.SelectedID, m)
+			if err != nil {
+				m.Dialog.Message = "Couldn't find the selected entry"
+				return m, nil
+			}
 			if m.DebugMode {
 				log.Println("Update entry action:", action, a, s)
 			}
 			m.UpdateMessage = action
-			return m, requestWallabagEntryUpdate(
-				sID,
-				a,
-				s,
-				p,
-			)
+			return m, entryUpdateCommand(m, sID, a, s, p)
+
+		// Open original URL:
+		case m.Keys["openOriginal"]:
+			if index := getSelectedEntryIndex(m.Entries, m.SelectedID); index >= 0 {
+				return m, requestOpenURL(m.Entries[index].URL)
+			}
+
+		// Copy original URL:
+		case m.Keys["copyOriginal"]:
+			if index := getSelectedEntryIndex(m.Entries, m.SelectedID); index >= 0 {
+				return m, requestCopyURL(m.Entries[index].URL)
+			}
+
+		// Read next or previous entry of the list:
+		case m.Keys["nextEntry"], m.Keys["previousEntry"]:
+			offset := 1
+			boundary := "This is the last article of the list"
+			if msg.String() == m.Keys["previousEntry"] {
+				offset = -1
+				boundary = "This is the first article of the list"
+			}
+			rows := getTableRows(m.Entries, m.Options.Filters, getRowsOptions(m))
+			position, id := getAdjacentRowID(rows, m.SelectedID, offset)
+			if position < 0 {
+				boundary = "This article isn't in the list anymore"
+			}
+			if id == 0 {
+				m.UpdateMessage = boundary
+				return m, clearUpdateMessageCmd()
+			}
+			// Keep list selection on the read entry:
+			setTableCursor(&m.Table, position)
+			saveScrollPosition(m)
+			m.Viewport.GotoTop()
+			return m, selectEntryCommand(id)
+
+		// Archive entry and read the next one:
+		case m.Keys["archiveAndNext"]:
+			index := getSelectedEntryIndex(m.Entries, m.SelectedID)
+			if index < 0 {
+				m.Dialog.Message = "Couldn't find the selected entry"
+				return m, nil
+			}
+			entry := m.Entries[index]
+			p := 0
+			if entry.IsPublic {
+				p = 1
+			}
+			update := entryUpdateCommand(m, entry.ID, 1, entry.IsStarred, p)
+
+			// Next entry is retrieved before the archived one leaves the list:
+			rows := getTableRows(m.Entries, m.Options.Filters, getRowsOptions(m))
+			position, id := getAdjacentRowID(rows, m.SelectedID, 1)
+			saveScrollPosition(m)
+			m.Viewport.GotoTop()
+			if id == 0 {
+				// Last entry, back to the list:
+				m.CurrentView = m.BrowsingView
+				m.SelectedID = 0
+				return m, update
+			}
+			setTableCursor(&m.Table, position)
+			return m, tea.Batch(update, selectEntryCommand(id))
+
+		// Metadata panel, kept for next articles:
+		case m.Keys["toggleMetadata"]:
+			m.ShowMetadata = !m.ShowMetadata
+			refreshDetailViewport(m)
+
+		// Search in the article:
+		case m.Keys["searchArticle"]:
+			m.Dialog.TextInput.Placeholder = "Search term"
+			m.Dialog.TextInput.CharLimit = 100
+			m.Dialog.TextInput.SetValue(m.ArticleSearch.Term)
+			m.Dialog.TextInput.CursorEnd()
+			m.Dialog.ShowInput = true
+			m.Dialog.Action = "search article"
+			m.Dialog.Message = "Search in the article (empty to clear):\n"
+			m.CurrentView = "dialog"
 
 		// Open links in entry:
-		case "L":
+		case m.Keys["openLink"]:
 			// Configure textinput:
 			m.Dialog.TextInput.Placeholder = "Link number"
 			m.Dialog.TextInput.CharLimit = 3
@@ -85,15 +204,20 @@ func updateEntryView(msg tea.Msg, m *model) (tea.Model, tea.Cmd) {
 			m.CurrentView = "dialog"
 
 		// Open or Copy URL:
-		case "O", "Y":
-			entry := &m.Entries[getSelectedEntryIndex(m.Entries, m.SelectedID)]
+		case m.Keys["open"], m.Keys["copy"]:
+			index := getSelectedEntryIndex(m.Entries, m.SelectedID)
+			if index < 0 {
+				m.Dialog.Message = "Couldn't find the selected entry"
+				return m, nil
+			}
+			entry := &m.Entries[index]
 			url := entry.URL
 			// If entry is public, open the public link:
-			if entry.IsPublic {
-				url = wallabago.Config.WallabagURL + "/share/" + entry.UID
+			if publicURL := getEntryPublicURL(entry, wallabago.Config.WallabagURL); publicURL != "" {
+				url = publicURL
 			}
 
-			if msg.String() == "O" {
+			if msg.String() == m.Keys["open"] {
 				// Open URL in browser:
 				if err := openLinkInBrowser(url); err != nil {
 					m.Dialog.Message = "Couldn't open link in browser"
@@ -104,7 +228,7 @@ func updateEntryView(msg tea.Msg, m *model) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 				m.UpdateMessage = "Link opened in browser"
-			} else if msg.String() == "Y" {
+			} else if msg.String() == m.Keys["copy"] {
 				// Copy URL:
 				if err := copyLinkToClipboard(url); err != nil {
 					m.Dialog.Message = "Couldn't copy link"
@@ -117,17 +241,65 @@ func updateEntryView(msg tea.Msg, m *model) (tea.Model, tea.Cmd) {
 				m.UpdateMessage = "Link copied"
 			}
 
-			return m, tea.Tick(time.Second*5, func(t time.Time) tea.Msg {
-				return wallabagoResponseClearMsg(true)
-			})
+			return m, clearUpdateMessageCmd()
+
+		// Export as markdown:
+		case m.Keys["export"]:
+			index := getSelectedEntryIndex(m.Entries, m.SelectedID)
+			if index < 0 {
+				m.Dialog.Message = "Couldn't find the selected entry"
+				return m, nil
+			}
+			path, err := exportEntry(&m.Entries[index], m.ExportPath)
+			if err != nil {
+				m.Dialog.Message = "Couldn't export article:\n" + err.Error()
+				if m.DebugMode {
+					log.Println("Error while exporting article")
+					log.Println(err)
+				}
+				return m, nil
+			}
+			m.UpdateMessage = "Article exported to " + path
+
+			return m, clearUpdateMessageCmd()
+
+		// Edit tags:
+		case m.Keys["editTags"]:
+			openEditTagsDialog(m, m.SelectedID)
+
+		// Retrieve the entry again from wallabag:
+		case m.Keys["refreshEntry"]:
+			if m.RefreshingID > 0 {
+				return m, nil
+			}
+			m.RefreshingID = m.SelectedID
+			return m, tea.Batch(requestWallabagEntryRefresh(m.Ctx, m.SelectedID), m.Spinner.Tick)
 
 		// Delete:
-		case "D":
+		case m.Keys["delete"]:
 			sID := m.SelectedID
 			m.SelectedID = 0
-			m.CurrentView = "list"
-			return m, requestWallabagEntryDelete(sID)
+			m.CurrentView = m.BrowsingView
+			return m, entryDeleteCommand(m, sID)
 		}
+
+	// Scroll article with mouse wheel:
+	case tea.MouseMsg:
+		switch msg.Type {
+		case tea.MouseWheelUp:
+			m.Viewport.LineUp(mouseWheelLines)
+		case tea.MouseWheelDown:
+			m.Viewport.LineDown(mouseWheelLines)
+		}
+		return m, nil
+
+	case spinner.TickMsg:
+		// Spin only while the entry is refreshed:
+		if m.RefreshingID > 0 {
+			m.Spinner, cmd = m.Spinner.Update(msg)
+			return m, cmd
+		}
+		return m, nil
 	}
 
 	m.Viewport, cmd = m.Viewport.Update(msg)
@@ -142,72 +314,282 @@ func updateListView(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "enter":
-			if !m.Reloading {
-				sID, _ := strconv.Atoi(m.Table.SelectedRow()[0])
+		case m.Keys["select"]:
+			if m.Reloading {
+				return m, nil
+			}
+			if sID := getSelectedRowID(m.Table, m.NbFilteredEntries); sID > 0 {
 				return m, selectEntryCommand(sID)
 			}
-		case "j", "down":
+		case m.Keys["down"], "down":
 			m.Table.MoveDown(1)
 		case "pgdown":
-			m.Table.MoveDown(10)
-		case "k", "up":
+			m.Table.MoveDown(getPageJump(m.PageJumpRows, m.Table.Height()))
+		case m.Keys["up"], "up":
 			m.Table.MoveUp(1)
 		case "pgup":
-			m.Table.MoveUp(10)
-		case "alt+[H":
+			m.Table.MoveUp(getPageJump(m.PageJumpRows, m.Table.Height()))
+		// Half page, depending on the window size:
+		case "ctrl+d":
+			m.Table.MoveDown(getHalfPage(m.Table.Height()))
+		case "ctrl+u":
+			m.Table.MoveUp(getHalfPage(m.Table.Height()))
+		case m.Keys["top"]:
+			// Top key needs to be pressed twice (eg: "gg"):
+			if top, cmd := isDoubleKeyPress(&m, msg.String()); !top {
+				return m, cmd
+			}
 			m.Table.GotoTop()
-		case "alt+[F":
+		case "home":
+			m.Table.GotoTop()
+		case m.Keys["bottom"], "end":
 			m.Table.GotoBottom()
-		case "q":
+		case m.Keys["quit"]:
 			// If search active, clean it and don't quit:
 			if m.Options.Filters.Search != "" {
 				return m, func() tea.Msg {
 					return walgotSearchEntryMsg("")
 				}
 			}
-			return m, tea.Quit
-		case "r":
+			// Same for tag filter:
+			if m.Options.Filters.Tag != "" {
+				return m, func() tea.Msg {
+					return walgotFilterTagMsg("")
+				}
+			}
+			// Same for date range filter:
+			if m.Options.Filters.DateRange != "" {
+				setDateRange(&m, "", "", time.Time{}, time.Time{})
+				return m, nil
+			}
+			// Same for wallabag search:
+			if m.Options.Filters.ServerSearch != "" {
+				clearServerSearch(&m)
+				return m, nil
+			}
+			return m, requestQuit(&m)
+		case m.Keys["reload"]:
 			// If already reloading, do nothing
 			if m.Reloading {
 				return m, nil
 			}
-			// Status as reloading:
-			m.Reloading = true
-			// Reset number of entries:
-			m.TotalEntriesOnServer = 0
-			return m, requestWallabagNbEntries
+			return m, reloadEntries(&m)
+
+		// Go to a line or an entry ID:
+		case m.Keys["jump"]:
+			if m.Reloading {
+				return m, nil
+			}
+			m.Dialog.TextInput.Placeholder = "Line number or #ID"
+			m.Dialog.TextInput.CharLimit = 20
+			m.Dialog.TextInput.Reset()
+			m.Dialog.ShowInput = true
+			m.Dialog.Action = "jump"
+			m.Dialog.Message = "Go to line, or to entry ID with #:\n"
+			m.CurrentView = "dialog"
+
+		// Tags of loaded entries:
+		case m.Keys["tagsView"]:
+			m.CurrentView = "tags"
+			setTagsTable(&m, 0)
+
+		// Last log lines:
+		case m.Keys["logs"]:
+			m.CurrentView = "logs"
+			setLogsViewport(&m)
+
+		// Check the number of entries on server, without reloading them:
+		case m.Keys["serverCount"]:
+			if m.Reloading {
+				return m, nil
+			}
+			if m.Paginated {
+				m.UpdateMessage = "Number of articles is already up to date in pagination mode"
+				return m, clearUpdateMessageCmd()
+			}
+			m.UpdateMessage = "Checking number of articles on wallabag…"
+			return m, requestWallabagServerCount(m.Ctx)
+
+		// Switch to another wallabag account:
+		case m.Keys["switchProfile"]:
+			if m.Reloading {
+				return m, nil
+			}
+			if len(m.ProfileCredentials) < 2 {
+				m.UpdateMessage = "No other profile configured (see Profiles option)"
+				return m, clearUpdateMessageCmd()
+			}
+			m.CurrentView = "profiles"
+			setProfilesTable(&m)
+
+		// Switch between compact and comfortable list:
+		case m.Keys["toggleDensity"]:
+			m.CompactList = !m.CompactList
+			windowSizeUpdate(&m)
+
+		// Browse entries grouped by domain:
+		case m.Keys["groupByDomain"]:
+			m.CurrentView = "grouped"
+			m.BrowsingView = "grouped"
+			m.GroupedCursor = 0
+
+		// Clear cache, after confirmation:
+		case m.Keys["clearCache"]:
+			if m.Reloading {
+				return m, nil
+			}
+			m.Dialog.ShowInput = false
+			m.Dialog.Action = "clear cache"
+			m.Dialog.Message = "Remove cache file and reload all entries from wallabag?\n\n" + m.CacheFile
+			m.CurrentView = "dialog"
+
+		// Export filtered entries, directory is asked first:
+		case m.Keys["exportAll"]:
+			if m.Reloading {
+				return m, nil
+			}
+			// Configure textinput:
+			m.Dialog.TextInput.Placeholder = "Directory"
+			m.Dialog.TextInput.CharLimit = 0
+			// Pre-fill with export directory:
+			m.Dialog.TextInput.Reset()
+			m.Dialog.TextInput.SetValue(filepath.Dir(m.ExportPath))
+			m.Dialog.TextInput.CursorEnd()
+			// Display textinput
+			m.Dialog.ShowInput = true
+			m.Dialog.Action = "export"
+			m.Dialog.Message = fmt.Sprintf("Export %d articles as markdown to directory:\n", m.NbFilteredEntries)
+			m.CurrentView = "dialog"
+
+		// Export filtered entries metadata, file is asked first:
+		case m.Keys["exportJSON"]:
+			if m.Reloading {
+				return m, nil
+			}
+			// Configure textinput:
+			m.Dialog.TextInput.Placeholder = "File"
+			m.Dialog.TextInput.CharLimit = 0
+			// Pre-fill with a file in export directory:
+			m.Dialog.TextInput.Reset()
+			m.Dialog.TextInput.SetValue(filepath.Join(filepath.Dir(m.ExportPath), "walgot-entries.ndjson"))
+			m.Dialog.TextInput.CursorEnd()
+			// Display textinput
+			m.Dialog.ShowInput = true
+			m.Dialog.Action = "export list"
+			m.Dialog.Message = fmt.Sprintf("Export %d articles metadata as NDJSON to file:\n", m.NbFilteredEntries)
+			m.CurrentView = "dialog"
 
 		// Filters for the table list:
-		case "u", "s", "a", "p":
-			listViewFiltersUpdate(msg.String(), &m)
+		case m.Keys["filterUnread"], m.Keys["filterStarred"], m.Keys["filterArchived"], m.Keys["filterPublic"], m.Keys["cycleReadState"]:
+			if m.Paginated && m.Reloading {
+				return m, nil
+			}
+			switch msg.String() {
+			case m.Keys["filterUnread"]:
+				listViewFiltersUpdate("unread", &m)
+			case m.Keys["filterStarred"]:
+				listViewFiltersUpdate("starred", &m)
+			case m.Keys["filterArchived"]:
+				listViewFiltersUpdate("archived", &m)
+			case m.Keys["filterPublic"]:
+				listViewFiltersUpdate("public", &m)
+			case m.Keys["cycleReadState"]:
+				listViewFiltersUpdate("readState", &m)
+			}
+			// Filters are applied by wallabag in pagination mode:
+			if m.Paginated {
+				return m, requestPage(&m, 1)
+			}
+
+		// Change page, in pagination mode:
+		case m.Keys["nextPage"], m.Keys["previousPage"]:
+			if !m.Paginated || m.Reloading {
+				return m, nil
+			}
+			page := m.CurrentPage + 1
+			if msg.String() == m.Keys["previousPage"] {
+				page = m.CurrentPage - 1
+			}
+			if page < 1 || page > m.TotalPages {
+				return m, nil
+			}
+			return m, requestPage(&m, page)
+
+		// Sort the table list:
+		case m.Keys["cycleSort"], m.Keys["toggleSortOrder"]:
+			if m.Reloading {
+				return m, nil
+			}
+			if msg.String() == m.Keys["cycleSort"] {
+				listViewSortsUpdate("field", &m)
+			} else {
+				listViewSortsUpdate("order", &m)
+			}
+			// Sort by date is done by wallabag in pagination mode:
+			if m.Paginated {
+				return m, requestPage(&m, 1)
+			}
 
 		// Update entry status:
-		case "A", "S", "P":
-			sID, _ := strconv.Atoi(m.Table.SelectedRow()[0])
-			a, s, p, action := sendEntryUpdate(msg.String(), sID, &m)
+		case m.Keys["toggleArchive"], m.Keys["toggleStar"], m.Keys["togglePublic"]:
+			if m.Reloading {
+				return m, nil
+			}
+			// Batch update of selected entries:
+			if len(m.Marked) > 0 {
+				m.UpdateMessage = fmt.Sprintf("Updating %d entries…", len(m.Marked))
+				return m, requestMarkedEntriesUpdate(getEntryUpdateField(msg.String(), m.Keys), &m)
+			}
+			sID := getSelectedRowID(m.Table, m.NbFilteredEntries)
+			if sID == 0 {
+				return m, nil
+			}
+			a, s, p, action, err := sendEntryUpdate(getEntryUpdateField(msg.String(), m.Keys), sID, &m)
+			if err != nil {
+				m.Dialog.Message = "Couldn't find the selected entry"
+				return m, nil
+			}
 			if m.DebugMode {
 				log.Println("Update entry action:", action, a, s)
 			}
 			m.UpdateMessage = action
-			return m, requestWallabagEntryUpdate(
-				sID,
-				a,
-				s,
-				p,
-			)
+			return m, entryUpdateCommand(&m, sID, a, s, p)
+
+		// Open original URL:
+		case m.Keys["openOriginal"]:
+			if m.Reloading {
+				return m, nil
+			}
+			if index := getSelectedEntryIndex(m.Entries, getSelectedRowID(m.Table, m.NbFilteredEntries)); index >= 0 {
+				return m, requestOpenURL(m.Entries[index].URL)
+			}
+
+		// Copy original URL:
+		case m.Keys["copyOriginal"]:
+			if m.Reloading {
+				return m, nil
+			}
+			if index := getSelectedEntryIndex(m.Entries, getSelectedRowID(m.Table, m.NbFilteredEntries)); index >= 0 {
+				return m, requestCopyURL(m.Entries[index].URL)
+			}
 
 		// Open or Copy URL:
-		case "O", "Y":
-			sID, _ := strconv.Atoi(m.Table.SelectedRow()[0])
-			entry := m.Entries[getSelectedEntryIndex(m.Entries, sID)]
+		case m.Keys["open"], m.Keys["copy"]:
+			if m.Reloading {
+				return m, nil
+			}
+			index := getSelectedEntryIndex(m.Entries, getSelectedRowID(m.Table, m.NbFilteredEntries))
+			if index < 0 {
+				return m, nil
+			}
+			entry := m.Entries[index]
 			url := entry.URL
 			// If entry is public, open the public link:
-			if entry.IsPublic {
-				url = wallabago.Config.WallabagURL + "/share/" + entry.UID
+			if publicURL := getEntryPublicURL(&entry, wallabago.Config.WallabagURL); publicURL != "" {
+				url = publicURL
 			}
 
-			if msg.String() == "O" {
+			if msg.String() == m.Keys["open"] {
 				// Open URL in browser:
 				if err := openLinkInBrowser(url); err != nil {
 					m.Dialog.Message = "Couldn't open link in browser"
@@ -218,7 +600,7 @@ func updateListView(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 				m.UpdateMessage = "Link opened in browser"
-			} else if msg.String() == "Y" {
+			} else if msg.String() == m.Keys["copy"] {
 				// Copy URL:
 				if err := copyLinkToClipboard(url); err != nil {
 					m.Dialog.Message = "Couldn't copy link"
@@ -231,43 +613,163 @@ func updateListView(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 				m.UpdateMessage = "Link copied"
 			}
 
-			return m, tea.Tick(time.Second*5, func(t time.Time) tea.Msg {
-				return wallabagoResponseClearMsg(true)
-			})
+			return m, clearUpdateMessageCmd()
+
+		// Edit tags:
+		case m.Keys["editTags"]:
+			if m.Reloading {
+				return m, nil
+			}
+			if sID := getSelectedRowID(m.Table, m.NbFilteredEntries); sID > 0 {
+				openEditTagsDialog(&m, sID)
+			}
 
 		// Delete:
-		case "D":
+		case m.Keys["delete"]:
 			if m.Reloading {
 				return m, nil
 			}
-			sID, _ := strconv.Atoi(m.Table.SelectedRow()[0])
-			return m, requestWallabagEntryDelete(sID)
+			// Selected entries are deleted after confirmation:
+			if len(m.Marked) > 0 {
+				m.Dialog.ShowInput = false
+				m.Dialog.Action = "delete"
+				m.Dialog.Message = fmt.Sprintf("Delete the %d selected entries from wallabag?", len(m.Marked))
+				m.CurrentView = "dialog"
+				return m, nil
+			}
+			if sID := getSelectedRowID(m.Table, m.NbFilteredEntries); sID > 0 {
+				return m, entryDeleteCommand(&m, sID)
+			}
+
+		// Open selected entries, after confirmation if there are many of them:
+		case m.Keys["openSelected"]:
+			if m.Reloading || len(m.Marked) == 0 {
+				return m, nil
+			}
+			urls := getMarkedEntriesURLs(m.Entries, m.Marked)
+			if len(urls) > m.MaxOpenAtOnce {
+				m.Dialog.ShowInput = false
+				m.Dialog.Action = "open selected"
+				m.Dialog.Message = fmt.Sprintf("Open the %d selected articles in browser?", len(urls))
+				m.CurrentView = "dialog"
+				return m, nil
+			}
+			m.Marked = map[int]bool{}
+			refreshTableRows(&m)
+			return m, requestOpenURLs(urls)
+
+		// Select entry for batch actions:
+		case m.Keys["mark"]:
+			if m.Reloading {
+				return m, nil
+			}
+			if sID := getSelectedRowID(m.Table, m.NbFilteredEntries); sID > 0 {
+				if m.Marked[sID] {
+					delete(m.Marked, sID)
+				} else {
+					m.Marked[sID] = true
+				}
+				refreshTableRows(&m)
+				m.Table.MoveDown(1)
+			}
 
 		// Search:
-		case "/":
+		case m.Keys["search"]:
 			if m.Reloading {
 				return m, nil
 			}
 			// Configure textinput:
 			m.Dialog.TextInput.Placeholder = "Search"
 			m.Dialog.TextInput.CharLimit = 55
+			// Continue from current search, if any:
+			m.Dialog.TextInput.SetValue(m.Options.Filters.Search)
 			// Display textinput
 			m.Dialog.ShowInput = true
 			// Add search button:
 			m.Dialog.Action = "search"
 			// Dialog title:
-			m.Dialog.Message = "Filter by article's title:\n"
+			m.Dialog.Message = "Filter by article's title or domain:\n"
+			// Set current view to dialog:
+			m.CurrentView = "dialog"
+
+		// Filter by tag:
+		case m.Keys["filterTag"]:
+			if m.Reloading {
+				return m, nil
+			}
+			// Configure textinput:
+			m.Dialog.TextInput.Placeholder = "Tag"
+			m.Dialog.TextInput.CharLimit = 55
+			// Display textinput
+			m.Dialog.ShowInput = true
+			// Add tag filter button:
+			m.Dialog.Action = "filter tag"
+			// Dialog title:
+			m.Dialog.Message = "Filter by tag:\n"
+			// Set current view to dialog:
+			m.CurrentView = "dialog"
+
+		// Read a random entry of the list:
+		case m.Keys["randomEntry"]:
+			if m.Reloading {
+				return m, nil
+			}
+			id := getRandomEntryID(getFilteredEntries(m.Entries, m.Options.Filters), m.Rand)
+			if id == 0 {
+				m.UpdateMessage = "No article to pick"
+				return m, clearUpdateMessageCmd()
+			}
+			// Keep list selection on the read entry:
+			rows := getTableRows(m.Entries, m.Options.Filters, getRowsOptions(&m))
+			if position, _ := getAdjacentRowID(rows, id, 0); position >= 0 {
+				setTableCursor(&m.Table, position)
+			}
+			return m, selectEntryCommand(id)
+
+		// Filter by creation or update date:
+		case m.Keys["filterDate"]:
+			if m.Reloading {
+				return m, nil
+			}
+			m.Dialog.TextInput.Placeholder = "7d, 2024-01-01..2024-02-01, updated:2w"
+			m.Dialog.TextInput.CharLimit = 40
+			m.Dialog.TextInput.SetValue(m.Options.Filters.DateRange)
+			m.Dialog.ShowInput = true
+			m.Dialog.Action = "filter date"
+			m.Dialog.Message = "Filter by date range (empty to remove it):\n"
+			m.CurrentView = "dialog"
+
+		// Search on wallabag:
+		case m.Keys["wallabagSearch"]:
+			if m.Reloading {
+				return m, nil
+			}
+			// Configure textinput:
+			m.Dialog.TextInput.Placeholder = "Search"
+			m.Dialog.TextInput.CharLimit = 55
+			// Display textinput
+			m.Dialog.ShowInput = true
+			// Add search button:
+			m.Dialog.Action = "wallabag search"
+			// Dialog title:
+			m.Dialog.Message = "Search articles on wallabag:\n"
 			// Set current view to dialog:
 			m.CurrentView = "dialog"
 
 		// Add an entry:
-		case "N":
+		case m.Keys["add"], "N":
 			if m.Reloading {
 				return m, nil
 			}
 			// Configure textinput:
 			m.Dialog.TextInput.Placeholder = "URL"
 			m.Dialog.TextInput.CharLimit = 0
+			// Pre-fill with clipboard URL, if any:
+			m.Dialog.TextInput.Reset()
+			if url := getURLFromClipboard(); url != "" {
+				m.Dialog.TextInput.SetValue(url)
+				m.Dialog.TextInput.CursorEnd()
+			}
 			// Display textinput
 			m.Dialog.ShowInput = true
 			// Add search button:
@@ -279,6 +781,12 @@ func updateListView(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 
 		// Clean, if needed:
 		case "esc":
+			// Clear selection first:
+			if len(m.Marked) > 0 {
+				m.Marked = map[int]bool{}
+				refreshTableRows(&m)
+				return m, nil
+			}
 			if m.Options.Filters.Search != "" {
 				// Cleaning a search.
 				m.Options.Filters.Search = ""
@@ -287,45 +795,130 @@ func updateListView(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 					return walgotSearchEntryMsg("")
 				}
 			}
+			if m.Options.Filters.Tag != "" {
+				return m, func() tea.Msg {
+					return walgotFilterTagMsg("")
+				}
+			}
+			if m.Options.Filters.DateRange != "" {
+				setDateRange(&m, "", "", time.Time{}, time.Time{})
+				return m, nil
+			}
+			if m.Options.Filters.ServerSearch != "" {
+				clearServerSearch(&m)
+			}
 		}
 
-	// When resizing the window, sizes needs to change everywhere…
-	case tea.WindowSizeMsg:
-		m.TermSize = termSize{msg.Width, msg.Height}
-		// TODO: Seems to bug when resizing though:
-		windowSizeUpdate(&m)
+	// Move in the list with mouse wheel,
+	// click on a column header to sort by this column:
+	case tea.MouseMsg:
+		if (msg.Type == tea.MouseWheelUp || msg.Type == tea.MouseWheelDown) && m.CurrentView == "list" {
+			if msg.Type == tea.MouseWheelUp {
+				m.Table.MoveUp(1)
+			} else {
+				m.Table.MoveDown(1)
+			}
+			return m, nil
+		}
+		if msg.Type != tea.MouseLeft || m.Reloading {
+			return m, nil
+		}
+		// Table header is below walgot header:
+		top := lipgloss.Height(m.headerView())
+		if msg.Y < top || msg.Y >= top+getTableHeaderHeight(m.CompactList) {
+			return m, nil
+		}
+		columns := createViewTableColumns(getListWidth(m.ListWidth, m.TermSize.Width, m.ShowPreview), m.Columns)
+		if i := getTableColumnAt(columns, msg.X); i >= 0 {
+			listViewSortByColumn(columns[i].Title, &m)
+		}
 
 	// Retrieved total number of entities from API:
 	case wallabagoResponseNbEntitiesMsg:
+		// Reload may have been canceled in the meantime:
+		if !m.Reloading && !m.AutoRefreshing {
+			return m, nil
+		}
 		m.TotalEntriesOnServer = int(msg)
 		// We now have the number of entries, we can trigger
 		// the process to retrieve all these entries
 		return m, tea.Batch(
 			requestWallabagEntries(
+				m.ReloadCtx,
 				m.TotalEntriesOnServer,
 				m.NbEntriesPerAPICall,
+				m.NbConcurrentAPICalls,
 				m.Options.Sorts.Field,
 				m.Options.Sorts.Order,
+				m.CacheFile,
+				m.CacheTTL,
+				// Automatic refresh retrieves entries from wallabag:
+				m.NoCache || m.AutoRefreshing,
 			),
 			m.Spinner.Tick,
 		)
 
+	// Retrieving entities from API, still in progress:
+	case wallabagoLoadProgressMsg:
+		// Messages of a canceled reload are still read until the end:
+		if msg.Total > 0 && m.Reloading {
+			m.LoadProgress = float64(msg.Done) / float64(msg.Total)
+			m.LoadedEntries = msg.Loaded
+		}
+		return m, waitForWallabagEntries(msg.messages)
+
 	// Retrieved entities from API, data has changed:
 	case wallabagoResponseEntitiesMsg:
-		// Response received, we are not reloading anymore:
-		m.Reloading = false
-		m.Entries = msg
+		cmd = tea.Batch(getNewEntriesNotification(&m, msg), sendPendingChanges(&m))
+		setEntries(&m, msg)
+		// Number of entries on server is unknown offline:
+		if m.Offline {
+			m.TotalEntriesOnServer = len(msg)
+		}
 		if m.DebugMode {
 			log.Println("wallabagoResponseEntityMsg", len(msg))
 		}
-		m.Table.SetRows(getTableRows(m.Entries, m.Options.Filters, m.TermSize.Width))
+
+	// Retrieved entities from API, but some pages are missing:
+	case wallabagoResponsePartialEntitiesMsg:
+		cmd = tea.Batch(getNewEntriesNotification(&m, msg.Entries), sendPendingChanges(&m))
+		setEntries(&m, msg.Entries)
+		// Warning stays displayed until the next message:
+		m.UpdateMessage = fmt.Sprintf(
+			"Warning: %d of %d pages couldn't be retrieved, list is incomplete (%s to reload)",
+			msg.FailedPages,
+			msg.TotalPages,
+			m.Keys["reload"],
+		)
+
+	// Retrieved a page of entries, in pagination mode:
+	case wallabagoResponsePageMsg:
+		if !m.Reloading && !m.AutoRefreshing {
+			return m, nil
+		}
+		m.Reloading = false
+		m.Entries = applyPendingChanges(msg.Entries, m.PendingChanges)
+		m.ServerSearchEntries = nil
+		clearExcerpts(&m)
+		m.ContentCache.clear()
+		m.CurrentPage = msg.Page
+		m.TotalPages = msg.Pages
+		m.TotalEntriesOnServer = msg.Total
+		sortEntries(m.Entries, m.Options.Sorts)
+		refreshTableRows(&m)
+		// Automatic refresh keeps the selected entry:
+		if !m.AutoRefreshing {
+			m.Table.GotoTop()
+		}
+		m.AutoRefreshing = false
+		cmd = sendPendingChanges(&m)
 
 	// Added entry response:
 	case wallabagoResponseAddEntryMsg:
 		// Add new entry at the top.
 		m.Entries = append([]wallabago.Item{msg.Entry}, m.Entries...)
 		// Recalculate table rows:
-		m.Table.SetRows(getTableRows(m.Entries, m.Options.Filters, m.TermSize.Width))
+		refreshTableRows(&m)
 		// Wallabag API send a 200 even if the URL isn't good.
 		// Unfortunately, it means checking the content of the entry…
 		if strings.Contains(
@@ -336,34 +929,59 @@ func updateListView(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 		} else {
 			// Letting user know:
 			m.UpdateMessage = "Entry has been added successfully"
-			return m, tea.Tick(time.Second*5, func(t time.Time) tea.Msg {
-				return wallabagoResponseClearMsg(true)
-			})
+			return m, clearUpdateMessageCmd()
 		}
 
 	// Deleted entry response:
 	case wallabagoResponseDeleteEntryMsg:
 		// Remove deleted entry from model:
-		index := getSelectedEntryIndex(m.Entries, int(msg))
-		if index == 0 {
-			m.Entries = m.Entries[1:]
-		} else if index == len(m.Entries)-1 {
-			m.Entries = m.Entries[:len(m.Entries)-2]
-		} else {
+		if index := getSelectedEntryIndex(m.Entries, int(msg)); index >= 0 {
 			m.Entries = append(m.Entries[:index], m.Entries[index+1:]...)
 		}
-		m.Table.SetRows(getTableRows(m.Entries, m.Options.Filters, m.TermSize.Width))
+		refreshTableRows(&m)
 		// Letting user know:
 		m.UpdateMessage = "Entry has been deleted successfully"
-		return m, tea.Tick(time.Second*5, func(t time.Time) tea.Msg {
-			return wallabagoResponseClearMsg(true)
-		})
+		return m, clearUpdateMessageCmd()
 
 	// Search request:
 	case walgotSearchEntryMsg:
 		m.Options.Filters.Search = string(msg)
 		// Recalculate table rows:
-		m.Table.SetRows(getTableRows(m.Entries, m.Options.Filters, m.TermSize.Width))
+		refreshTableRows(&m)
+
+	// Wallabag search response:
+	case wallabagoResponseSearchMsg:
+		if len(msg.Entries) == 0 {
+			m.UpdateMessage = "No matches on wallabag for " + msg.Term
+			return m, clearUpdateMessageCmd()
+		}
+		// Results of a previous search aren't listed anymore:
+		removeServerSearchEntries(&m)
+		ids := map[int]bool{}
+		for _, e := range msg.Entries {
+			ids[e.ID] = true
+			// Entries not loaded yet are listed during the search only:
+			if getSelectedEntryIndex(m.Entries, e.ID) < 0 {
+				m.ServerSearchEntries = append(m.ServerSearchEntries, e)
+				m.Entries = append(m.Entries, e)
+			}
+		}
+		m.Options.Filters.ServerSearch = msg.Term
+		m.Options.Filters.ServerSearchIDs = ids
+		m.Table.GotoTop()
+		refreshTableRows(&m)
+		m.UpdateMessage = getServerSearchMessage(len(msg.Entries), m.NbFilteredEntries, msg.Term)
+		return m, clearUpdateMessageCmd()
+
+	// Tag filter request:
+	case walgotFilterTagMsg:
+		m.Options.Filters.Tag = strings.TrimSpace(string(msg))
+		// Tag filter is applied by wallabag in pagination mode:
+		if m.Paginated {
+			return m, requestPage(&m, 1)
+		}
+		// Recalculate table rows:
+		refreshTableRows(&m)
 
 	case spinner.TickMsg:
 		// Spin only if it is still displaying the reload screen:
@@ -376,6 +994,341 @@ func updateListView(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// Manage update messages for the grouped by domain view.
+// Messages other than keys are managed by the list view.
+func updateGroupedView(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return updateListView(msg, m)
+	}
+
+	rows := getGroupedRows(getDomainGroups(m.Entries, m.Options.Filters), m.ExpandedDomains)
+	// Rows may have changed since the last move:
+	cursor := m.GroupedCursor
+	if cursor >= len(rows) {
+		cursor = len(rows) - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+
+	switch keyMsg.String() {
+	case m.Keys["down"], "down":
+		cursor++
+	case "pgdown":
+		cursor += getPageJump(m.PageJumpRows, m.Viewport.Height)
+	case m.Keys["up"], "up":
+		cursor--
+	case "pgup":
+		cursor -= getPageJump(m.PageJumpRows, m.Viewport.Height)
+	case "ctrl+d":
+		cursor += getHalfPage(m.Viewport.Height)
+	case "ctrl+u":
+		cursor -= getHalfPage(m.Viewport.Height)
+	case m.Keys["top"]:
+		// Top key needs to be pressed twice (eg: "gg"):
+		if top, cmd := isDoubleKeyPress(&m, keyMsg.String()); !top {
+			return m, cmd
+		}
+		cursor = 0
+	case "home":
+		cursor = 0
+	case m.Keys["bottom"], "end":
+		cursor = len(rows) - 1
+	case m.Keys["select"], "right", "left":
+		if m.Reloading || len(rows) == 0 {
+			return m, nil
+		}
+		row := rows[cursor]
+		if row.ID > 0 && keyMsg.String() == m.Keys["select"] {
+			return m, selectEntryCommand(row.ID)
+		}
+		// Domain of an entry is already expanded:
+		if row.ID > 0 && keyMsg.String() == "right" {
+			return m, nil
+		}
+		if m.ExpandedDomains == nil {
+			m.ExpandedDomains = map[string]bool{}
+		}
+		switch keyMsg.String() {
+		case "right":
+			m.ExpandedDomains[row.Domain] = true
+		case "left":
+			m.ExpandedDomains[row.Domain] = false
+		default:
+			m.ExpandedDomains[row.Domain] = !row.Expanded
+		}
+		// Cursor goes back to the domain when collapsing it from an entry:
+		rows = getGroupedRows(getDomainGroups(m.Entries, m.Options.Filters), m.ExpandedDomains)
+		cursor = getDomainRowPosition(rows, row.Domain)
+	case m.Keys["reload"]:
+		if m.Reloading {
+			return m, nil
+		}
+		return m, reloadEntries(&m)
+	case m.Keys["tagsView"]:
+		m.CurrentView = "tags"
+		setTagsTable(&m, 0)
+		return m, nil
+	case m.Keys["logs"]:
+		m.CurrentView = "logs"
+		setLogsViewport(&m)
+		return m, nil
+	case m.Keys["groupByDomain"], m.Keys["quit"], "esc":
+		m.CurrentView = "list"
+		m.BrowsingView = "list"
+		return m, nil
+	}
+
+	if cursor >= len(rows) {
+		cursor = len(rows) - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	m.GroupedCursor = cursor
+
+	return m, nil
+}
+
+// Manage update messages for the tags view.
+// Messages other than keys are managed by the list view, tags are updated with entries.
+func updateTagsView(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		updated, cmd := updateListView(msg, m)
+		m = updated.(model)
+		setTagsTable(&m, m.TagsTable.Cursor())
+		return m, cmd
+	}
+
+	switch keyMsg.String() {
+	case m.Keys["down"], "down":
+		m.TagsTable.MoveDown(1)
+	case "pgdown":
+		m.TagsTable.MoveDown(getPageJump(m.PageJumpRows, m.TagsTable.Height()))
+	case m.Keys["up"], "up":
+		m.TagsTable.MoveUp(1)
+	case "pgup":
+		m.TagsTable.MoveUp(getPageJump(m.PageJumpRows, m.TagsTable.Height()))
+	case "ctrl+d":
+		m.TagsTable.MoveDown(getHalfPage(m.TagsTable.Height()))
+	case "ctrl+u":
+		m.TagsTable.MoveUp(getHalfPage(m.TagsTable.Height()))
+	case m.Keys["top"]:
+		// Top key needs to be pressed twice (eg: "gg"):
+		if top, cmd := isDoubleKeyPress(&m, keyMsg.String()); !top {
+			return m, cmd
+		}
+		m.TagsTable.GotoTop()
+	case "home":
+		m.TagsTable.GotoTop()
+	case m.Keys["bottom"], "end":
+		m.TagsTable.GotoBottom()
+	case m.Keys["select"]:
+		row := getSelectedRow(m.TagsTable, m.NbTags)
+		if len(row) == 0 {
+			return m, nil
+		}
+		// Back to the entries, filtered by the selected tag:
+		tag := row[0]
+		m.CurrentView = m.BrowsingView
+		return m, func() tea.Msg {
+			return walgotFilterTagMsg(tag)
+		}
+	case m.Keys["tagsView"], m.Keys["quit"], "esc":
+		m.CurrentView = m.BrowsingView
+	}
+
+	return m, nil
+}
+
+// Regenerate the tags table from loaded entries, the cursor is set at the given position.
+func setTagsTable(m *model, cursor int) {
+	tags := getTagCounts(m.Entries)
+	m.TagsTable = createTagsTable(tags, getListWidth(m.ListWidth, m.TermSize.Width, false), m.Table.Height(), m.CompactList, m.Theme)
+	m.NbTags = len(tags)
+	setTableCursor(&m.TagsTable, cursor)
+}
+
+// Manage update messages for the logs view.
+// Messages other than keys are managed by the list view.
+func updateLogsView(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return updateListView(msg, m)
+	}
+
+	switch keyMsg.String() {
+	case m.Keys["down"], "down":
+		m.LogsViewport.LineDown(1)
+	case m.Keys["up"], "up":
+		m.LogsViewport.LineUp(1)
+	case "pgdown", "ctrl+d":
+		m.LogsViewport.HalfViewDown()
+	case "pgup", "ctrl+u":
+		m.LogsViewport.HalfViewUp()
+	case m.Keys["top"]:
+		// Top key needs to be pressed twice (eg: "gg"):
+		if top, cmd := isDoubleKeyPress(&m, keyMsg.String()); !top {
+			return m, cmd
+		}
+		m.LogsViewport.GotoTop()
+	case "home":
+		m.LogsViewport.GotoTop()
+	case m.Keys["bottom"], "end":
+		m.LogsViewport.GotoBottom()
+	case m.Keys["reload"]:
+		// Logs written since the view has been opened:
+		setLogsViewport(&m)
+	case m.Keys["logs"], m.Keys["quit"], "esc":
+		m.CurrentView = m.BrowsingView
+	}
+
+	return m, nil
+}
+
+// Fill the logs view with the last log lines, newest at the bottom.
+func setLogsViewport(m *model) {
+	m.LogsViewport.SetContent(getLogsText(m.Logs.getLines(), m.LogsViewport.Width))
+	m.LogsViewport.GotoBottom()
+}
+
+// Manage update messages for the profiles view.
+// Messages other than keys are managed by the list view.
+func updateProfilesView(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return updateListView(msg, m)
+	}
+
+	switch keyMsg.String() {
+	case m.Keys["down"], "down":
+		m.ProfilesTable.MoveDown(1)
+	case m.Keys["up"], "up":
+		m.ProfilesTable.MoveUp(1)
+	case "home":
+		m.ProfilesTable.GotoTop()
+	case m.Keys["bottom"], "end":
+		m.ProfilesTable.GotoBottom()
+	case m.Keys["select"]:
+		row := getSelectedRow(m.ProfilesTable, m.NbProfiles)
+		if len(row) == 0 {
+			return m, nil
+		}
+		m.CurrentView = m.BrowsingView
+		profile := getProfileName(row[0])
+		if profile == m.Profile {
+			return m, nil
+		}
+		return m, switchProfile(&m, profile)
+	case m.Keys["switchProfile"], m.Keys["quit"], "esc":
+		m.CurrentView = m.BrowsingView
+	}
+
+	return m, nil
+}
+
+// Regenerate the profiles table, the cursor is set on the active profile.
+func setProfilesTable(m *model) {
+	profiles := getProfileNames(m.ProfileCredentials)
+	m.ProfilesTable = createProfilesTable(profiles, m.Profile, getListWidth(m.ListWidth, m.TermSize.Width, false), m.Table.Height(), m.CompactList, m.Theme)
+	m.NbProfiles = len(profiles)
+	for i, p := range profiles {
+		if p == m.Profile {
+			setTableCursor(&m.ProfilesTable, i)
+		}
+	}
+}
+
+// Use the given profile, its entries are cached in their own file.
+// Same for its pending changes, loaded from its queue file.
+func setProfile(m *model, profile string) {
+	m.Profile = profile
+	m.CacheFile = config.ProfileFilePath(m.BaseCacheFile, profile)
+	m.QueueFile = config.ProfileFilePath(m.BaseQueueFile, profile)
+	changes, err := loadPendingChanges(m.QueueFile)
+	if err != nil {
+		log.Println("Couldn't load pending changes from", m.QueueFile, err)
+	}
+	m.PendingChanges = changes
+}
+
+// Switch to the given profile: entries of the previous one are dropped
+// and those of the profile are loaded, with its credentials.
+// Running requests of the previous profile are canceled, their responses are ignored.
+func switchProfile(m *model, profile string) tea.Cmd {
+	if err := api.SwitchCredentials(m.ProfileCredentials[profile]); err != nil {
+		return func() tea.Msg {
+			return wallabagoResponseErrorMsg{
+				message:        "Error:\n couldn't switch to profile " + getProfileLabel(profile),
+				wallabagoError: err,
+			}
+		}
+	}
+	m.Cancel()
+	m.Ctx, m.Cancel = context.WithCancel(context.Background())
+	m.ReloadCancel = nil
+	setProfile(m, profile)
+	m.EndpointHost, m.EndpointUser = api.GetEndpoint()
+
+	// Entries and selections of the previous profile:
+	m.Entries = []wallabago.Item{}
+	m.Marked = map[int]bool{}
+	clearExcerpts(m)
+	m.ContentCache.clear()
+	m.SelectedID = 0
+	m.RefreshingID = 0
+	m.TotalEntriesOnServer = 0
+	m.ServerCount = 0
+	m.CurrentPage = 1
+	m.Options.Filters.ServerSearch = ""
+	m.Options.Filters.ServerSearchIDs = nil
+	m.ServerSearchEntries = nil
+	refreshTableRows(m)
+
+	// Only cached entries are used offline:
+	if m.Offline {
+		m.Reloading = true
+		return requestCachedEntries(m.CacheFile)
+	}
+	return reloadEntries(m)
+}
+
+// Check if the key completes a double key press (eg: "gg").
+// Otherwise the key is kept until the next one, or until doubleKeyDelay expires.
+func isDoubleKeyPress(m *model, key string) (bool, tea.Cmd) {
+	if m.PendingKey == key {
+		m.PendingKey = ""
+		return true, nil
+	}
+	m.PendingKey = key
+	m.PendingKeyID++
+	id := m.PendingKeyID
+
+	return false, tea.Tick(doubleKeyDelay, func(t time.Time) tea.Msg {
+		return walgotPendingKeyTimeoutMsg(id)
+	})
+}
+
+// Open the dialog editing tags of the given entry, pre-filled with its current tags.
+func openEditTagsDialog(m *model, id int) {
+	index := getSelectedEntryIndex(m.Entries, id)
+	if index < 0 {
+		return
+	}
+	m.Dialog.TextInput.Placeholder = "Tags, comma separated"
+	m.Dialog.TextInput.CharLimit = 0
+	m.Dialog.TextInput.Reset()
+	m.Dialog.TextInput.SetValue(strings.Join(getEntryTagLabels(&m.Entries[index]), ", "))
+	m.Dialog.TextInput.CursorEnd()
+	m.Dialog.ShowInput = true
+	m.Dialog.Action = "edit tags"
+	m.Dialog.EntryID = id
+	m.Dialog.Message = "Edit tags of " + m.Entries[index].Title + ":\n"
+	m.CurrentView = "dialog"
+}
+
 // Manage update messages for dialog view.
 func updateDialogView(msg tea.Msg, m *model) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
@@ -384,10 +1337,17 @@ func updateDialogView(msg tea.Msg, m *model) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
+			// Cancelling a search removes the search filter:
+			if m.Dialog.Action == "search" {
+				m.Options.Filters.Search = ""
+				m.Dialog.TextInput.Reset()
+				refreshTableRows(m)
+			}
 			// Close and reset dialog box:
 			m.Dialog.Message = ""
 			m.Dialog.ShowInput = false
 			m.Dialog.Action = ""
+			m.Dialog.EntryID = 0
 			m.Dialog.TextInput.Blur()
 			// Search input is not resetted though, just in case.
 			return m, nil
@@ -395,14 +1355,41 @@ func updateDialogView(msg tea.Msg, m *model) (tea.Model, tea.Cmd) {
 		case "enter":
 			input := m.Dialog.TextInput.Value()
 			action := m.Dialog.Action
+			entryID := m.Dialog.EntryID
+			// Invalid URLs are reported without closing the dialog:
+			if action == "add" && !isValidURL(strings.TrimSpace(input)) {
+				m.Dialog.Message = "Invalid URL, please enter a valid URL:\n"
+				return m, nil
+			}
+			// Same for invalid date ranges:
+			var dateField string
+			var since, until time.Time
+			if action == "filter date" && strings.TrimSpace(input) != "" {
+				var err error
+				if dateField, since, until, err = parseDateRange(input, time.Now()); err != nil {
+					m.Dialog.Message = err.Error() + ", enter a date range (eg: 7d or 2024-01-01..2024-02-01):\n"
+					return m, nil
+				}
+			}
+			// Same for lines and IDs not listed:
+			position := -1
+			if action == "jump" {
+				rows := getTableRows(m.Entries, m.Options.Filters, getRowsOptions(m))
+				var err error
+				if position, err = getJumpPosition(rows, input); err != nil {
+					m.Dialog.Message = err.Error() + ", go to line or #ID:\n"
+					return m, nil
+				}
+			}
 			// Cleaning dialog box:
 			m.Dialog.Message = ""
 			m.Dialog.ShowInput = false
 			m.Dialog.Action = ""
+			m.Dialog.EntryID = 0
 			m.Dialog.TextInput.Blur()
 			m.Dialog.TextInput.Reset()
 			// Next screen should be on filtered list:
-			m.CurrentView = "list"
+			m.CurrentView = m.BrowsingView
 
 			// Per action command:
 			switch action {
@@ -412,14 +1399,124 @@ func updateDialogView(msg tea.Msg, m *model) (tea.Model, tea.Cmd) {
 					return walgotSearchEntryMsg(input)
 				})
 
+			case "filter tag":
+				cmds = append(cmds, func() tea.Msg {
+					return walgotFilterTagMsg(input)
+				})
+
+			case "wallabag search":
+				if strings.TrimSpace(input) == "" {
+					return m, nil
+				}
+				m.UpdateMessage = "Searching on wallabag…"
+				return m, requestWallabagSearch(m.Ctx, input, m.NbEntriesPerAPICall)
+
 			// Save entry:
 			case "add":
-				return m, requestWallabagAddEntry(input)
+				return m, requestWallabagAddEntry(m.Ctx, strings.TrimSpace(input))
 
-			case "open link":
-				_, links := getCleanedContentAndLinks(
-					m.Entries[getSelectedEntryIndex(m.Entries, m.SelectedID)].Content,
+			case "jump":
+				setTableCursor(&m.Table, position)
+
+			case "filter date":
+				setDateRange(m, strings.TrimSpace(input), dateField, since, until)
+
+			case "edit tags":
+				index := getSelectedEntryIndex(m.Entries, entryID)
+				if index < 0 {
+					return m, nil
+				}
+				labels := parseTagsInput(input)
+				if strings.EqualFold(strings.Join(labels, ","), strings.Join(getEntryTagLabels(&m.Entries[index]), ",")) {
+					m.UpdateMessage = "Tags unchanged"
+					return m, clearUpdateMessageCmd()
+				}
+				m.UpdateMessage = "Updating tags…"
+				return m, requestWallabagSetTags(m.Ctx, m.Entries[index], labels)
+
+			case "export":
+				directory, err := config.ExpandPath(strings.TrimSpace(input))
+				if err != nil {
+					return m, func() tea.Msg {
+						return wallabagoResponseErrorMsg{
+							message:        "Error:\n invalid export directory " + input,
+							wallabagoError: err,
+						}
+					}
+				}
+				entries := getFilteredEntries(m.Entries, m.Options.Filters)
+				return m, requestEntriesExport(entries, directory, filepath.Base(m.ExportPath))
+
+			case "export list":
+				path, err := config.ExpandPath(strings.TrimSpace(input))
+				if err == nil {
+					err = exportEntriesJSON(getFilteredEntries(m.Entries, m.Options.Filters), path)
+				}
+				if err != nil {
+					return m, func() tea.Msg {
+						return wallabagoResponseErrorMsg{
+							message:        "Error:\n couldn't export articles to " + input,
+							wallabagoError: err,
+						}
+					}
+				}
+				m.UpdateMessage = fmt.Sprintf("%d articles exported to %s", m.NbFilteredEntries, path)
+				return m, clearUpdateMessageCmd()
+
+			case "delete":
+				var deletes []tea.Cmd
+				for id := range m.Marked {
+					deletes = append(deletes, entryDeleteCommand(m, id))
+				}
+				m.Marked = map[int]bool{}
+				refreshTableRows(m)
+				return m, tea.Batch(deletes...)
+
+			case "open selected":
+				urls := getMarkedEntriesURLs(m.Entries, m.Marked)
+				m.Marked = map[int]bool{}
+				refreshTableRows(m)
+				return m, requestOpenURLs(urls)
+
+			case "quit":
+				return m, quitCommand(m)
+
+			case "clear cache":
+				if err := removeCacheFile(m.CacheFile); err != nil {
+					return m, func() tea.Msg {
+						return wallabagoResponseErrorMsg{
+							message:        "Error:\n couldn't remove cache file " + m.CacheFile,
+							wallabagoError: err,
+						}
+					}
+				}
+				m.UpdateMessage = "Cache cleared"
+				return m, tea.Batch(
+					reloadEntries(m),
+					clearUpdateMessageCmd(),
 				)
+
+			case "search article":
+				m.ArticleSearch = walgotArticleSearch{Term: input}
+				refreshDetailViewport(m)
+				if input == "" {
+					return m, nil
+				}
+				if len(m.ArticleSearch.Lines) == 0 {
+					m.UpdateMessage = "No match for " + input + " in the article"
+					return m, clearUpdateMessageCmd()
+				}
+				// First match from the current position:
+				m.ArticleSearch.Current = getFirstMatchFrom(m.ArticleSearch.Lines, m.Viewport.YOffset)
+				m.Viewport.SetYOffset(m.ArticleSearch.Lines[m.ArticleSearch.Current])
+
+			case "open link":
+				index := getSelectedEntryIndex(m.Entries, m.SelectedID)
+				if index < 0 {
+					m.Dialog.Message = "Couldn't find the selected entry"
+					return m, nil
+				}
+				_, links := getCleanedContentAndLinks(m.Entries[index].Content)
 				selected, err := strconv.Atoi(input)
 				if err != nil {
 					m.Dialog.Message = "Couldn't find link number " + input
@@ -451,9 +1548,7 @@ func updateDialogView(msg tea.Msg, m *model) (tea.Model, tea.Cmd) {
 
 				cmds = append(
 					cmds,
-					tea.Tick(time.Second*5, func(t time.Time) tea.Msg {
-						return wallabagoResponseClearMsg(true)
-					}),
+					clearUpdateMessageCmd(),
 				)
 			}
 		}
@@ -464,45 +1559,447 @@ func updateDialogView(msg tea.Msg, m *model) (tea.Model, tea.Cmd) {
 	m.Dialog.TextInput, cmd = m.Dialog.TextInput.Update(msg)
 	cmds = append(cmds, cmd)
 
+	// Search is filtering the list while typing:
+	if m.Dialog.Action == "search" && m.Dialog.TextInput.Value() != m.Options.Filters.Search {
+		m.Options.Filters.Search = m.Dialog.TextInput.Value()
+		refreshTableRows(m)
+	}
+
 	return m, tea.Batch(cmds...)
 }
 
+// Quit walgot, after confirmation if enabled or if changes are not sent yet.
+func requestQuit(m *model) tea.Cmd {
+	if !m.ConfirmQuit && len(m.PendingChanges) == 0 {
+		return quitCommand(m)
+	}
+	m.Dialog.ShowInput = false
+	m.Dialog.Action = "quit"
+	m.Dialog.Message = "Quit walgot?"
+	if len(m.PendingChanges) > 0 {
+		m.Dialog.Message = fmt.Sprintf("%d changes are not sent to wallabag yet, they will be sent at next start.\n\nQuit walgot?", len(m.PendingChanges))
+	}
+	m.CurrentView = "dialog"
+
+	return nil
+}
+
+// Send an entry update to wallabag, it is queued offline.
+func entryUpdateCommand(m *model, id, archive, starred, public int) tea.Cmd {
+	if m.Offline {
+		return queueChangeCommand(walgotPendingChange{ID: id, Archive: archive, Starred: starred, Public: public})
+	}
+
+	return requestWallabagEntryUpdate(m.Ctx, id, archive, starred, public)
+}
+
+// Delete an entry on wallabag, it is queued offline.
+func entryDeleteCommand(m *model, id int) tea.Cmd {
+	if m.Offline {
+		return queueChangeCommand(walgotPendingChange{ID: id, Delete: true})
+	}
+
+	return requestWallabagEntryDelete(m.Ctx, id)
+}
+
+// Queue a change to send it later, it is applied to entries right away.
+func queuePendingChange(m *model, change walgotPendingChange) {
+	change.QueuedAt = time.Now()
+	m.PendingChanges = queueChange(m.PendingChanges, change)
+	savePendingChangesOf(m)
+	m.Entries = applyPendingChanges(m.Entries, []walgotPendingChange{change})
+	if change.Delete {
+		delete(m.Marked, change.ID)
+	}
+	refreshTableRows(m)
+	if m.SelectedID == change.ID {
+		refreshDetailViewport(m)
+	}
+}
+
+// Save pending changes in the queue file, errors are only logged.
+func savePendingChangesOf(m *model) {
+	if err := savePendingChanges(m.QueueFile, m.PendingChanges); err != nil {
+		log.Println("Couldn't save pending changes in", m.QueueFile, err)
+	}
+}
+
+// Send pending changes to wallabag, unless offline or already sending them.
+func sendPendingChanges(m *model) tea.Cmd {
+	if m.Offline || m.SendingChanges || len(m.PendingChanges) == 0 {
+		return nil
+	}
+	m.SendingChanges = true
+
+	return requestPendingChangesSend(m.Ctx, m.QueueFile, m.PendingChanges)
+}
+
+// Reload entries from wallabag (or cache).
+func reloadEntries(m *model) tea.Cmd {
+	// Status as reloading:
+	m.Reloading = true
+	m.AutoRefreshing = false
+	m.LoadProgress = 0
+	m.LoadedEntries = 0
+	// Entries may have changed, forget scroll positions:
+	m.ScrollPositions = map[int]int{}
+	// Only the current page is reloaded in pagination mode:
+	if m.Paginated {
+		return requestPage(m, m.CurrentPage)
+	}
+	// Reset number of entries:
+	m.TotalEntriesOnServer = 0
+	m.ServerCount = 0
+	return requestWallabagNbEntries(newReloadContext(m))
+}
+
+// Refresh entries from wallabag in background, list actions stay available
+// and the selected entry is kept.
+func autoRefreshEntries(m *model) tea.Cmd {
+	m.AutoRefreshing = true
+	if m.Paginated {
+		return requestWallabagEntriesPage(newReloadContext(m), m.CurrentPage, m.NbEntriesPerAPICall, m.Options.Filters, m.Options.Sorts)
+	}
+	return requestWallabagNbEntries(newReloadContext(m))
+}
+
+// Create the context of a new reload, the previous one is canceled if still running.
+func newReloadContext(m *model) context.Context {
+	if m.ReloadCancel != nil {
+		m.ReloadCancel()
+	}
+	m.ReloadCtx, m.ReloadCancel = context.WithCancel(m.Ctx)
+	return m.ReloadCtx
+}
+
+// Cancel the running reload, entries loaded before are kept.
+func cancelReload(m *model) {
+	if m.ReloadCancel != nil {
+		m.ReloadCancel()
+	}
+	m.UpdateMessage = "Reload canceled"
+	if m.AutoRefreshing {
+		m.UpdateMessage = "Refresh canceled"
+	}
+	m.Reloading = false
+	m.AutoRefreshing = false
+	m.LoadProgress = 0
+	m.LoadedEntries = 0
+	refreshTableRows(m)
+}
+
+// Replace entries with the retrieved ones.
+func setEntries(m *model, entries []wallabago.Item) {
+	// Response received, we are not reloading anymore:
+	m.Reloading = false
+	m.AutoRefreshing = false
+	m.LoadProgress = 0
+	m.LoadedEntries = 0
+	m.Entries = applyPendingChanges(entries, m.PendingChanges)
+	m.ServerSearchEntries = nil
+	clearExcerpts(m)
+	m.ContentCache.clear()
+	sortEntries(m.Entries, m.Options.Sorts)
+	refreshTableRows(m)
+}
+
+// Return the notification about entries retrieved by an automatic refresh and
+// not seen before, if enabled. Retrieved entries are marked as seen.
+func getNewEntriesNotification(m *model, entries []wallabago.Item) tea.Cmd {
+	nbNew := countUnseenEntries(entries, m.SeenIDs)
+	// First entries are not new:
+	notify := m.NotifyNewEntries && m.AutoRefreshing && m.SeenIDs != nil && nbNew > 0
+	m.SeenIDs = map[int]bool{}
+	for _, e := range entries {
+		m.SeenIDs[e.ID] = true
+	}
+	if !notify {
+		return nil
+	}
+
+	return requestNewEntriesNotification(nbNew)
+}
+
+// Forget excerpts of previous entries, if the excerpt column is displayed.
+func clearExcerpts(m *model) {
+	if m.Excerpts != nil {
+		m.Excerpts = map[int]string{}
+	}
+}
+
+// Request a page of entries, in pagination mode.
+func requestPage(m *model, page int) tea.Cmd {
+	m.Reloading = true
+	m.AutoRefreshing = false
+	return tea.Batch(
+		requestWallabagEntriesPage(newReloadContext(m), page, m.NbEntriesPerAPICall, m.Options.Filters, m.Options.Sorts),
+		m.Spinner.Tick,
+	)
+}
+
+// Scroll to the next (offset 1) or previous (offset -1) match of the search
+// in the article, cycling at both ends.
+func jumpToMatch(m *model, offset int) {
+	nb := len(m.ArticleSearch.Lines)
+	if nb == 0 {
+		return
+	}
+	m.ArticleSearch.Current = (m.ArticleSearch.Current + offset + nb) % nb
+	m.Viewport.SetYOffset(m.ArticleSearch.Lines[m.ArticleSearch.Current])
+}
+
+// Return to the list if the entry being read isn't loaded anymore.
+func closeMissingEntry(m *model) {
+	if m.SelectedID <= 0 || getSelectedEntryIndex(m.Entries, m.SelectedID) >= 0 {
+		return
+	}
+	delete(m.ScrollPositions, m.SelectedID)
+	m.SelectedID = 0
+	m.ArticleSearch = walgotArticleSearch{}
+	m.Viewport.GotoTop()
+	if m.CurrentView == "detail" {
+		m.CurrentView = m.BrowsingView
+	}
+}
+
+// Save scroll position of the entry being read.
+func saveScrollPosition(m *model) {
+	if m.SelectedID <= 0 {
+		return
+	}
+	if m.Viewport.YOffset > 0 {
+		m.ScrollPositions[m.SelectedID] = m.Viewport.YOffset
+	} else {
+		delete(m.ScrollPositions, m.SelectedID)
+	}
+}
+
+// Remove wallabag search results filter.
+func clearServerSearch(m *model) {
+	m.Options.Filters.ServerSearch = ""
+	m.Options.Filters.ServerSearchIDs = nil
+	removeServerSearchEntries(m)
+	refreshTableRows(m)
+}
+
+// Remove entries that were added to the list by the wallabag search only.
+func removeServerSearchEntries(m *model) {
+	if len(m.ServerSearchEntries) == 0 {
+		return
+	}
+	added := map[int]bool{}
+	for _, e := range m.ServerSearchEntries {
+		added[e.ID] = true
+	}
+	entries := make([]wallabago.Item, 0, len(m.Entries))
+	for _, e := range m.Entries {
+		if !added[e.ID] {
+			entries = append(entries, e)
+		}
+	}
+	m.Entries = entries
+	m.ServerSearchEntries = nil
+}
+
+// Rows rendering options of the list view.
+func getRowsOptions(m *model) walgotRowsOptions {
+	return walgotRowsOptions{
+		MaxWidth:      getListWidth(m.ListWidth, m.TermSize.Width, m.ShowPreview),
+		Columns:       m.Columns,
+		Compact:       m.CompactList,
+		DateFormat:    m.DateFormat,
+		RelativeDates: m.RelativeDates,
+		Marked:        m.Marked,
+		Excerpts:      m.Excerpts,
+	}
+}
+
+// Regenerate table rows from entries and filters.
+// Cursor is kept within the new rows boundaries.
+func refreshTableRows(m *model) {
+	setTableRows(m, getSelectedRowID(m.Table, m.NbFilteredEntries), m.Table.Cursor())
+}
+
+// Set table rows, the cursor stays on the given entry (or the entry being read)
+// if still listed, otherwise on the given position.
+func setTableRows(m *model, cursorID, cursor int) {
+	if m.SelectedID > 0 {
+		cursorID = m.SelectedID
+	}
+	rows := getTableRows(m.Entries, m.Options.Filters, getRowsOptions(m))
+	m.Table.SetRows(rows)
+	m.NbFilteredEntries = len(rows)
+	m.FilteredReadingTime = getTotalReadingTime(m.Entries, m.Options.Filters)
+	if position, _ := getAdjacentRowID(rows, cursorID, 0); cursorID > 0 && position >= 0 {
+		setTableCursor(&m.Table, position)
+		return
+	}
+	setTableCursor(&m.Table, cursor)
+}
+
+// Update the given field ("archive", "star" or "public") of all selected entries.
+// Field is set on all of them, or unset if it is already set on all of them.
+// Selection is cleared, rows are updated when wallabag responds.
+func requestMarkedEntriesUpdate(field string, m *model) tea.Cmd {
+	var indexes []int
+	allSet := true
+	for id := range m.Marked {
+		index := getSelectedEntryIndex(m.Entries, id)
+		if index < 0 {
+			continue
+		}
+		indexes = append(indexes, index)
+		if !isEntryFieldSet(&m.Entries[index], field) {
+			allSet = false
+		}
+	}
+	// Keep requests order stable:
+	sort.Ints(indexes)
+
+	var updates []tea.Cmd
+	for _, index := range indexes {
+		entry := &m.Entries[index]
+		if isEntryFieldSet(entry, field) != allSet {
+			continue
+		}
+		// Toggling the field of this entry gives the wanted value:
+		a, s, p, _, err := sendEntryUpdate(field, entry.ID, m)
+		if err != nil {
+			continue
+		}
+		updates = append(updates, entryUpdateCommand(m, entry.ID, a, s, p))
+	}
+
+	m.Marked = map[int]bool{}
+	refreshTableRows(m)
+
+	return tea.Batch(updates...)
+}
+
 // Manage update message for updated entry via API.
 func updatedEntryInModel(m *model, updatedEntry wallabago.Item) {
+	index := getSelectedEntryIndex(m.Entries, updatedEntry.ID)
+	if index < 0 {
+		// Entry isn't loaded (anymore?), nothing to refresh:
+		if m.DebugMode {
+			log.Println("Updated entry not found in model:", updatedEntry.ID)
+		}
+		m.UpdateMessage = "Entry has been updated"
+		return
+	}
 	// Add a message update. No need for a popup here.
-	m.UpdateMessage = "Entry has been updated"
+	m.UpdateMessage = getEntryUpdateMessage(m.Entries[index], updatedEntry, wallabago.Config.WallabagURL)
 	// The entry in the model needs to be updated to avoid refreshing all via API
-	m.Entries[getSelectedEntryIndex(m.Entries, updatedEntry.ID)] = updatedEntry
+	m.Entries[index] = updatedEntry
 	// Update the table rows so that's it udpated in the list view:
-	m.Table.SetRows(getTableRows(m.Entries, m.Options.Filters, m.TermSize.Width))
+	refreshTableRows(m)
+	// Tags are displayed in the reading view:
+	if m.SelectedID == updatedEntry.ID {
+		refreshDetailViewport(m)
+	}
+}
+
+// Replace an entry with the one retrieved again from wallabag.
+// Its converted content and excerpt may have changed, they are computed again.
+func refreshedEntryInModel(m *model, entry wallabago.Item) {
+	index := getSelectedEntryIndex(m.Entries, entry.ID)
+	if index < 0 {
+		m.UpdateMessage = "Entry has been refreshed"
+		return
+	}
+	m.Entries[index] = entry
+	m.ContentCache.remove(entry.ID)
+	delete(m.Excerpts, entry.ID)
+	refreshTableRows(m)
+	if m.SelectedID == entry.ID {
+		refreshDetailViewport(m)
+	}
+	m.UpdateMessage = "Entry has been refreshed"
+}
+
+// Set the date range filter, an empty dateRange removes it.
+func setDateRange(m *model, dateRange, field string, since, until time.Time) {
+	m.Options.Filters.DateRange = dateRange
+	m.Options.Filters.DateField = field
+	m.Options.Filters.Since = since
+	m.Options.Filters.Until = until
+	refreshTableRows(m)
 }
 
 // Manage keybinds changing filters on listView.
-func listViewFiltersUpdate(msg string, m *model) {
-	if msg == "u" {
-		m.Options.Filters.Unread = !m.Options.Filters.Unread
-		// Unread and Archived can't be selected at the same time:
-		if m.Options.Filters.Unread {
-			m.Options.Filters.Archived = false
-		}
-	} else if msg == "a" {
-		m.Options.Filters.Archived = !m.Options.Filters.Archived
-		// Unread and Archived can't be selected at the same time:
-		if m.Options.Filters.Archived {
-			m.Options.Filters.Unread = false
-		}
-	} else if msg == "s" {
+func listViewFiltersUpdate(filter string, m *model) {
+	if filter == "unread" || filter == "archived" {
+		// Read state is unread, archived or all, toggling the current one shows all:
+		if m.Options.Filters.ReadState == filter {
+			m.Options.Filters.ReadState = ""
+		} else {
+			m.Options.Filters.ReadState = filter
+		}
+	} else if filter == "readState" {
+		m.Options.Filters.ReadState = getNextReadState(m.Options.Filters.ReadState)
+	} else if filter == "starred" {
 		m.Options.Filters.Starred = !m.Options.Filters.Starred
-	} else if msg == "p" {
+	} else if filter == "public" {
 		m.Options.Filters.Public = !m.Options.Filters.Public
 	}
 
-	m.Table.SetRows(getTableRows(m.Entries, m.Options.Filters, m.TermSize.Width))
+	refreshTableRows(m)
+}
+
+// Manage keybinds changing sort on listView.
+// "field" cycles through sort fields, "order" toggles sort order.
+func listViewSortsUpdate(change string, m *model) {
+	if change == "field" {
+		fields := []string{"created", "updated", "title", "reading"}
+		next := 0
+		for i, f := range fields {
+			if f == m.Options.Sorts.Field {
+				next = (i + 1) % len(fields)
+			}
+		}
+		m.Options.Sorts.Field = fields[next]
+	} else if change == "order" {
+		if m.Options.Sorts.Order == "asc" {
+			m.Options.Sorts.Order = "desc"
+		} else {
+			m.Options.Sorts.Order = "asc"
+		}
+	}
+
+	sortEntries(m.Entries, m.Options.Sorts)
+	refreshTableRows(m)
+}
+
+// Sort list by the given column.
+// Sorting again by the same column toggles sort order.
+func listViewSortByColumn(column string, m *model) {
+	fields := map[string]string{
+		"Title":     "title",
+		"Est. read": "reading",
+		"Created":   "created",
+		"Updated":   "updated",
+	}
+	field, ok := fields[column]
+	if !ok {
+		return
+	}
+
+	if m.Options.Sorts.Field == field {
+		listViewSortsUpdate("order", m)
+		return
+	}
+	m.Options.Sorts.Field = field
+	sortEntries(m.Entries, m.Options.Sorts)
+	refreshTableRows(m)
 }
 
 // Retrieve updates variable.
-func sendEntryUpdate(msg string, sID int, m *model) (int, int, int, string) {
-	entry := m.Entries[getSelectedEntryIndex(m.Entries, sID)]
+func sendEntryUpdate(field string, sID int, m *model) (int, int, int, string, error) {
+	index := getSelectedEntryIndex(m.Entries, sID)
+	if index < 0 {
+		return 0, 0, 0, "", errors.New("entry not found: " + strconv.Itoa(sID))
+	}
+	entry := m.Entries[index]
 	action := "Toggled entry status: "
 	a := entry.IsArchived
 	s := entry.IsStarred
@@ -511,7 +2008,7 @@ func sendEntryUpdate(msg string, sID int, m *model) (int, int, int, string) {
 		p = 1
 	}
 
-	if msg == "A" {
+	if field == "archive" {
 		if entry.IsArchived == 0 {
 			action = "archive"
 			a = 1
@@ -519,7 +2016,7 @@ func sendEntryUpdate(msg string, sID int, m *model) (int, int, int, string) {
 			action = "read"
 			a = 0
 		}
-	} else if msg == "S" {
+	} else if field == "star" {
 		if entry.IsStarred == 0 {
 			action = "starred"
 			s = 1
@@ -527,7 +2024,7 @@ func sendEntryUpdate(msg string, sID int, m *model) (int, int, int, string) {
 			action = "unstarred"
 			s = 0
 		}
-	} else if msg == "P" {
+	} else if field == "public" {
 		if !entry.IsPublic {
 			action = "publish"
 			p = 1
@@ -537,5 +2034,5 @@ func sendEntryUpdate(msg string, sID int, m *model) (int, int, int, string) {
 		}
 	}
 
-	return a, s, p, action
+	return a, s, p, action, nil
 }
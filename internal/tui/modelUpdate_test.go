@@ -0,0 +1,381 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Strubbl/wallabago/v7"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSetTableRows(t *testing.T) {
+	entries := []wallabago.Item{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+	var tests = []struct {
+		inputFilters   walgotTableFilters
+		inputCursorID  int
+		inputCursor    int
+		inputSelected  int
+		expectedCursor int
+	}{
+		// Cursor stays on the same entry:
+		{walgotTableFilters{}, 3, 0, 0, 2},
+		// Entry being read has priority:
+		{walgotTableFilters{}, 3, 0, 2, 1},
+		// Entry isn't listed anymore, position is kept:
+		{walgotTableFilters{ServerSearch: "x", ServerSearchIDs: map[int]bool{1: true, 2: true, 4: true}}, 3, 2, 0, 2},
+		{walgotTableFilters{ServerSearch: "x", ServerSearchIDs: map[int]bool{1: true}}, 3, 2, 0, 0},
+		// No entry listed:
+		{walgotTableFilters{ServerSearch: "x"}, 3, 2, 0, -1},
+	}
+
+	for _, test := range tests {
+		m := model{
+			Entries:    entries,
+			SelectedID: test.inputSelected,
+			Table:      createViewTable(100, 10, nil, false, defaultTheme),
+			Options:    walgotTableOptions{Filters: test.inputFilters},
+		}
+		setTableRows(&m, test.inputCursorID, test.inputCursor)
+		if m.Table.Cursor() != test.expectedCursor {
+			t.Errorf("setTableRows(%v, %v, %v): expectedCursor %v, got %v", test.inputFilters, test.inputCursorID, test.inputCursor, test.expectedCursor, m.Table.Cursor())
+		}
+		if test.expectedCursor >= 0 && getSelectedRowID(m.Table, m.NbFilteredEntries) == 0 {
+			t.Errorf("setTableRows(%v, %v, %v): no entry selected", test.inputFilters, test.inputCursorID, test.inputCursor)
+		}
+	}
+}
+
+func TestWindowSizeUpdate(t *testing.T) {
+	var tests = []termSize{{0, 0}, {1, 1}, {10, 3}, {30, 8}, {200, 60}}
+
+	for _, test := range tests {
+		m := model{
+			TermSize:   test,
+			Entries:    []wallabago.Item{{ID: 1, Title: "One"}},
+			SelectedID: 1,
+			Table:      createViewTable(100, 10, nil, false, defaultTheme),
+			Keys:       defaultKeybindings,
+			Theme:      defaultTheme,
+		}
+		windowSizeUpdate(&m)
+		if m.Table.Height() < 1 || m.Viewport.Height < 1 || m.LogsViewport.Height < 1 {
+			t.Errorf("windowSizeUpdate(%v): expected heights of at least 1, got %v/%v/%v", test, m.Table.Height(), m.Viewport.Height, m.LogsViewport.Height)
+		}
+		if m.Viewport.Width < 1 {
+			t.Errorf("windowSizeUpdate(%v): expected viewport width of at least 1, got %v", test, m.Viewport.Width)
+		}
+		// Views can be rendered:
+		m.View()
+		m.SelectedID = 0
+		m.View()
+	}
+}
+
+func TestCancelReload(t *testing.T) {
+	m := model{
+		Ctx:       context.Background(),
+		Entries:   []wallabago.Item{{ID: 1}, {ID: 2}},
+		Reloading: true,
+		Table:     createViewTable(100, 10, nil, false, defaultTheme),
+	}
+	previous := newReloadContext(&m)
+	current := newReloadContext(&m)
+	if previous.Err() == nil {
+		t.Errorf("newReloadContext: previous reload context isn't canceled")
+	}
+
+	cancelReload(&m)
+	if current.Err() == nil {
+		t.Errorf("cancelReload: reload context isn't canceled")
+	}
+	if m.Reloading {
+		t.Errorf("cancelReload: expected Reloading false, got true")
+	}
+	// Entries loaded before are kept:
+	if m.NbFilteredEntries != 2 {
+		t.Errorf("cancelReload: expected 2 entries, got %v", m.NbFilteredEntries)
+	}
+}
+
+func TestIsDoubleKeyPress(t *testing.T) {
+	var tests = []struct {
+		inputPending string
+		inputKey     string
+		expected     bool
+		expectedCmd  bool
+	}{
+		{"", "g", false, true},
+		{"g", "g", true, false},
+		{"j", "g", false, true},
+	}
+
+	for _, test := range tests {
+		m := model{PendingKey: test.inputPending}
+		result, cmd := isDoubleKeyPress(&m, test.inputKey)
+		if result != test.expected {
+			t.Errorf("isDoubleKeyPress(%v, %v): expected %v, got %v", test.inputPending, test.inputKey, test.expected, result)
+		}
+		if (cmd != nil) != test.expectedCmd {
+			t.Errorf("isDoubleKeyPress(%v, %v): expectedCmd %v, got %v", test.inputPending, test.inputKey, test.expectedCmd, cmd != nil)
+		}
+		// Key is kept until the next one, unless the sequence is complete:
+		if result == (m.PendingKey == test.inputKey) {
+			t.Errorf("isDoubleKeyPress(%v, %v): unexpected pending key %v", test.inputPending, test.inputKey, m.PendingKey)
+		}
+	}
+}
+
+func TestRequestQuit(t *testing.T) {
+	var tests = []struct {
+		inputConfirmQuit bool
+		expectedQuit     bool
+		expectedAction   string
+	}{
+		{false, true, ""},
+		{true, false, "quit"},
+	}
+
+	for _, test := range tests {
+		m := model{ConfirmQuit: test.inputConfirmQuit}
+		cmd := requestQuit(&m)
+		if (cmd != nil) != test.expectedQuit {
+			t.Errorf("requestQuit(%v): expectedQuit %v, got %v", test.inputConfirmQuit, test.expectedQuit, cmd != nil)
+		}
+		if m.Dialog.Action != test.expectedAction {
+			t.Errorf("requestQuit(%v): expectedAction %v, got %v", test.inputConfirmQuit, test.expectedAction, m.Dialog.Action)
+		}
+	}
+}
+
+func TestQuitWhileReloading(t *testing.T) {
+	m := model{
+		Ready:        true,
+		Keys:         defaultKeybindings,
+		Theme:        defaultTheme,
+		TermSize:     termSize{100, 30},
+		ConfirmQuit:  true,
+		Reloading:    true,
+		CurrentView:  "list",
+		BrowsingView: "list",
+		Table:        createViewTable(100, 10, nil, false, defaultTheme),
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	m = updated.(model)
+	if m.Dialog.Action != "quit" {
+		t.Fatalf("quit while reloading: expected quit dialog, got %q", m.Dialog.Action)
+	}
+
+	// Progress is still read with the dialog opened, retrieval isn't blocked:
+	messages := make(chan tea.Msg)
+	updated, cmd := m.Update(wallabagoLoadProgressMsg{Done: 1, Total: 2, Loaded: 30, messages: messages})
+	m = updated.(model)
+	if cmd == nil {
+		t.Errorf("progress while quit dialog opened: expected a command waiting for next message, got nil")
+	}
+	if m.LoadProgress != 0.5 || m.LoadedEntries != 30 {
+		t.Errorf("progress while quit dialog opened: expected 0.5 / 30, got %v / %v", m.LoadProgress, m.LoadedEntries)
+	}
+}
+
+func TestAutoRefresh(t *testing.T) {
+	entries := []wallabago.Item{{ID: 1}, {ID: 2}, {ID: 3}}
+	var tests = []struct {
+		inputSelected      int
+		inputDialog        string
+		expectedRefreshing bool
+	}{
+		{0, "", true},
+		// Not refreshed while reading or answering a dialog:
+		{2, "", false},
+		{0, "Quit walgot?", false},
+	}
+
+	for _, test := range tests {
+		m := model{
+			Ready:               true,
+			Ctx:                 context.Background(),
+			AutoRefreshInterval: time.Minute,
+			Keys:                defaultKeybindings,
+			Theme:               defaultTheme,
+			TermSize:            termSize{100, 30},
+			Entries:             entries,
+			SelectedID:          test.inputSelected,
+			CurrentView:         "list",
+			BrowsingView:        "list",
+			Table:               createViewTable(100, 10, nil, false, defaultTheme),
+		}
+		m.Dialog.Message = test.inputDialog
+		setTableRows(&m, 0, 1)
+
+		updated, cmd := m.Update(walgotAutoRefreshMsg(true))
+		m = updated.(model)
+		if cmd == nil {
+			t.Errorf("autoRefresh(%v, %q): expected next refresh to be scheduled, got nil", test.inputSelected, test.inputDialog)
+		}
+		if m.AutoRefreshing != test.expectedRefreshing {
+			t.Errorf("autoRefresh(%v, %q): expectedRefreshing %v, got %v", test.inputSelected, test.inputDialog, test.expectedRefreshing, m.AutoRefreshing)
+		}
+		// List actions stay available:
+		if m.Reloading {
+			t.Errorf("autoRefresh(%v, %q): expected list not to be reloading", test.inputSelected, test.inputDialog)
+		}
+		if !test.expectedRefreshing {
+			continue
+		}
+
+		// Refreshed entries, the cursor stays on the selected entry:
+		updated, _ = m.Update(wallabagoResponseEntitiesMsg([]wallabago.Item{{ID: 4}, {ID: 1}, {ID: 2}, {ID: 3}}))
+		m = updated.(model)
+		if m.AutoRefreshing || getSelectedRowID(m.Table, m.NbFilteredEntries) != 2 {
+			t.Errorf("autoRefresh(%v, %q): expected entry 2 selected after refresh, got %v (refreshing %v)", test.inputSelected, test.inputDialog, getSelectedRowID(m.Table, m.NbFilteredEntries), m.AutoRefreshing)
+		}
+
+		// Esc cancels a refresh:
+		updated, _ = m.Update(walgotAutoRefreshMsg(true))
+		m = updated.(model)
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		m = updated.(model)
+		if m.AutoRefreshing || m.UpdateMessage != "Refresh canceled" {
+			t.Errorf("autoRefresh(%v, %q): expected refresh to be canceled, got %v / %q", test.inputSelected, test.inputDialog, m.AutoRefreshing, m.UpdateMessage)
+		}
+	}
+}
+
+func TestServerSearch(t *testing.T) {
+	m := model{
+		Entries: []wallabago.Item{{ID: 1, IsArchived: 0}, {ID: 2, IsArchived: 1}},
+		Table:   createViewTable(100, 10, nil, false, defaultTheme),
+		Options: walgotTableOptions{Filters: walgotTableFilters{ReadState: "unread"}},
+	}
+	refreshTableRows(&m)
+
+	// Archived match is hidden by the read state filter, entry 3 isn't loaded:
+	updated, _ := updateListView(wallabagoResponseSearchMsg{Term: "go", Entries: []wallabago.Item{{ID: 2, IsArchived: 1}, {ID: 3, IsArchived: 0}}}, m)
+	m = updated.(model)
+	if m.NbFilteredEntries != 1 || getSelectedRowID(m.Table, m.NbFilteredEntries) != 3 {
+		t.Errorf("server search: expected entry 3 only to be shown, got %v rows (%v selected)", m.NbFilteredEntries, getSelectedRowID(m.Table, m.NbFilteredEntries))
+	}
+	if expected := "2 matches on wallabag for go, 1 shown with current filters"; m.UpdateMessage != expected {
+		t.Errorf("server search: expected message %q, got %q", expected, m.UpdateMessage)
+	}
+	if len(m.Entries) != 3 || len(m.ServerSearchEntries) != 1 {
+		t.Errorf("server search: expected 3 entries listed with 1 from search, got %v / %v", len(m.Entries), len(m.ServerSearchEntries))
+	}
+
+	// Entries not loaded are removed with the search:
+	clearServerSearch(&m)
+	if len(m.Entries) != 2 || m.ServerSearchEntries != nil || getSelectedEntryIndex(m.Entries, 3) >= 0 {
+		t.Errorf("clearServerSearch: expected entries 1 and 2 only, got %v", m.Entries)
+	}
+	if m.NbFilteredEntries != 1 {
+		t.Errorf("clearServerSearch: expected 1 row, got %v", m.NbFilteredEntries)
+	}
+}
+
+func TestJumpToMatch(t *testing.T) {
+	var tests = []struct {
+		inputCurrent    int
+		inputOffset     int
+		expectedCurrent int
+	}{
+		{0, 1, 1},
+		{2, 1, 0},
+		{0, -1, 2},
+		{1, -1, 0},
+	}
+
+	for _, test := range tests {
+		m := model{
+			Viewport:      viewport.New(80, 5),
+			ArticleSearch: walgotArticleSearch{Term: "go", Lines: []int{3, 10, 25}, Current: test.inputCurrent},
+		}
+		m.Viewport.SetContent(strings.Repeat("line\n", 50))
+		jumpToMatch(&m, test.inputOffset)
+		if m.ArticleSearch.Current != test.expectedCurrent {
+			t.Errorf("jumpToMatch(%v, %v): expected %v, got %v", test.inputCurrent, test.inputOffset, test.expectedCurrent, m.ArticleSearch.Current)
+		}
+		if m.Viewport.YOffset != m.ArticleSearch.Lines[test.expectedCurrent] {
+			t.Errorf("jumpToMatch(%v, %v): expected offset %v, got %v", test.inputCurrent, test.inputOffset, m.ArticleSearch.Lines[test.expectedCurrent], m.Viewport.YOffset)
+		}
+	}
+
+	// Nothing happens without match:
+	m := model{ArticleSearch: walgotArticleSearch{Term: "go"}}
+	jumpToMatch(&m, 1)
+}
+
+func TestUpdateProfilesViewSelect(t *testing.T) {
+	var tests = []struct {
+		inputProfiles []string
+		expectedView  string
+	}{
+		{nil, "profiles"},
+		{[]string{"", "work"}, "list"},
+	}
+
+	for _, test := range tests {
+		m := model{
+			Keys:          defaultKeybindings,
+			CurrentView:   "profiles",
+			BrowsingView:  "list",
+			ProfilesTable: createProfilesTable(test.inputProfiles, "", 80, 10, false, defaultTheme),
+			NbProfiles:    len(test.inputProfiles),
+		}
+		updated, _ := updateProfilesView(tea.KeyMsg{Type: tea.KeyEnter}, m)
+		if view := updated.(model).CurrentView; view != test.expectedView {
+			t.Errorf("updateProfilesView(%v): expected view %v, got %v", test.inputProfiles, test.expectedView, view)
+		}
+	}
+}
+
+func TestUpdateEntryViewMissingEntry(t *testing.T) {
+	var tests = []string{"E", "O", "Y"}
+
+	for _, test := range tests {
+		m := model{
+			Keys:       defaultKeybindings,
+			Entries:    []wallabago.Item{{ID: 1}},
+			SelectedID: 2,
+		}
+		updateEntryView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(test)}, &m)
+		if m.Dialog.Message != "Couldn't find the selected entry" {
+			t.Errorf("updateEntryView(%v): expected error dialog, got %v", test, m.Dialog.Message)
+		}
+	}
+}
+
+func TestCloseMissingEntry(t *testing.T) {
+	m := model{
+		Ready:           true,
+		Keys:            defaultKeybindings,
+		Theme:           defaultTheme,
+		TermSize:        termSize{100, 30},
+		Entries:         []wallabago.Item{{ID: 1}},
+		SelectedID:      2,
+		CurrentView:     "detail",
+		BrowsingView:    "list",
+		ScrollPositions: map[int]int{2: 10},
+		Table:           createViewTable(100, 10, nil, false, defaultTheme),
+	}
+	// Entry is gone, views are rendered without it:
+	m.View()
+
+	updated, _ := m.Update(wallabagoResponseClearMsg(true))
+	m = updated.(model)
+	if m.SelectedID != 0 || m.CurrentView != "list" {
+		t.Errorf("closeMissingEntry: expected list view without selection, got %v / %v", m.SelectedID, m.CurrentView)
+	}
+	if _, ok := m.ScrollPositions[2]; ok {
+		t.Errorf("closeMissingEntry: scroll position of the missing entry is kept")
+	}
+
+	// Entry still loaded:
+	m.SelectedID, m.CurrentView = 1, "detail"
+	closeMissingEntry(&m)
+	if m.SelectedID != 1 || m.CurrentView != "detail" {
+		t.Errorf("closeMissingEntry: expected entry 1 to stay selected, got %v / %v", m.SelectedID, m.CurrentView)
+	}
+}
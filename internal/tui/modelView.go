@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
 	"github.com/muesli/reflow/wordwrap"
 )
 
@@ -20,37 +21,73 @@ func (m model) headerView() string {
 		BorderStyle(lipgloss.NormalBorder()).
 		BorderBottom(true).
 		Align(lipgloss.Center)
+	nameStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.Theme["title"])).Bold(true)
 
 	subtitle := ""
 	if !m.Ready {
 		subtitle += " - Loading…"
+	} else if m.AutoRefreshing {
+		subtitle += " - Refreshing"
 	} else if m.Reloading {
 		subtitle += " - Reloading"
+	} else if m.Offline && m.SelectedID > 0 {
+		subtitle += " - Offline - Reading"
 	} else if m.SelectedID > 0 {
 		subtitle += " - Reading"
+	} else if m.CurrentView == "logs" {
+		subtitle += " - Logs"
+	} else if m.CurrentView == "tags" {
+		subtitle += " - Tags"
+	} else if m.CurrentView == "profiles" {
+		subtitle += " - Profiles"
 	} else {
+		if m.Offline {
+			subtitle += " - Offline"
+		}
+		if m.ReadOnly {
+			subtitle += " - Read-only"
+		}
+		if m.Options.Filters.ServerSearch != "" {
+			subtitle += " - Wallabag search results for " + m.Options.Filters.ServerSearch
+		}
 		if m.Options.Filters.Search != "" {
 			subtitle += " - Searching for " + m.Options.Filters.Search
 		}
-		if m.Options.Filters.Unread {
-			subtitle += " - Unread"
+		if m.Options.Filters.Tag != "" {
+			subtitle += " - Tag: " + m.Options.Filters.Tag
+		}
+		if m.Options.Filters.DateRange != "" {
+			subtitle += " - Date: " + m.Options.Filters.DateRange
 		}
+		// Read state is always displayed, "All" if not filtered:
+		subtitle += " - " + getReadStateLabel(m.Options.Filters.ReadState)
 		if m.Options.Filters.Starred {
 			subtitle += " - Starred"
 		}
-		if m.Options.Filters.Archived {
-			subtitle += " - Archived"
-		}
 		if m.Options.Filters.Public {
 			subtitle += " - Public"
 		}
-		if len(subtitle) == 0 && !m.Reloading {
-			subtitle = " - All"
+		if m.BrowsingView == "grouped" {
+			subtitle += " - By domain"
+		}
+		subtitle += " - Sort: " + m.Options.Sorts.Field
+		if m.Options.Sorts.Order == "asc" {
+			subtitle += " ↑"
+		} else {
+			subtitle += " ↓"
 		}
+		if m.Paginated {
+			subtitle += fmt.Sprintf(" - Page %d/%d", m.CurrentPage, m.TotalPages)
+		}
+	}
+
+	// Active profile, if not the default one:
+	if m.Profile != "" {
+		subtitle = " [" + m.Profile + "]" + subtitle
 	}
 
 	t := lipgloss.JoinHorizontal(lipgloss.Center,
-		lipgloss.NewStyle().Bold(true).Render("Walgot"),
+		nameStyle.Render("Walgot"),
 		lipgloss.NewStyle().Render(subtitle),
 	)
 
@@ -66,6 +103,10 @@ func (m model) footerView() string {
 		return ""
 	}
 
+	if m.ShowStatusLine {
+		text += lipgloss.NewStyle().Faint(true).Render(getStatusLine(m.EndpointHost, m.EndpointUser, m.Profile)) + "\n"
+	}
+
 	if len(m.UpdateMessage) > 0 {
 		text += lipgloss.NewStyle().Italic(true).Render(m.UpdateMessage)
 	} else if !m.Reloading {
@@ -73,11 +114,31 @@ func (m model) footerView() string {
 			NewStyle().
 			Bold(true).
 			Render(strconv.Itoa(m.TotalEntriesOnServer))
-		text += " articles loaded from wallabag"
+		if m.Offline {
+			text += " articles loaded from cache (offline)"
+		} else {
+			text += " articles loaded from wallabag"
+		}
+		text += getServerCountText(m.ServerCount, m.TotalEntriesOnServer)
+		// Number of articles matching current filters:
+		text += fmt.Sprintf(" -- %d of %d shown", m.NbFilteredEntries, len(m.Entries)-len(m.ServerSearchEntries))
+		if m.FilteredReadingTime > 0 {
+			text += " -- " + formatReadingTime(m.FilteredReadingTime) + " to read"
+		}
+		if len(m.Marked) > 0 {
+			text += fmt.Sprintf(" -- %d selected", len(m.Marked))
+		}
 	}
 
+	// Keybinds hint of the current view, same priority as updates:
 	if m.TermSize.Width > 80 {
-		text += "\n[r]eload -- Toggles: [u]nread, [s]tarred, [a]rchived -- [h]elp"
+		view := m.CurrentView
+		if m.Dialog.Message != "" {
+			view = "dialog"
+		} else if m.CurrentView != "help" && m.SelectedID > 0 {
+			view = "detail"
+		}
+		text += "\n" + getFooterHint(view, m.Keys)
 	}
 
 	return lipgloss.
@@ -90,6 +151,14 @@ func (m model) footerView() string {
 		Render(text)
 }
 
+// Message displayed instead of views when the terminal is too small.
+func termTooSmallView(m model) string {
+	return lipgloss.NewStyle().
+		Width(m.TermSize.Width).
+		Align(lipgloss.Center).
+		Render(fmt.Sprintf("Terminal too small (need at least %dx%d)", minTermWidth, minTermHeight))
+}
+
 // Return the main part of the view.
 func (m model) mainView() string {
 	if !m.Ready {
@@ -100,137 +169,218 @@ func (m model) mainView() string {
 		return reloadingView(m)
 	}
 
-	// Priority: dialog > help > detail > list.
-	if m.Dialog.Message != "" {
+	// Priority: search > dialog > help > detail > logs > tags > profiles > grouped > list.
+	if m.Dialog.Message != "" && m.Dialog.Action == "search" {
+		return searchView(&m)
+	} else if m.Dialog.Message != "" {
 		return dialogView(&m)
 	} else if m.CurrentView == "help" {
 		return helpView(m)
-	} else if m.SelectedID > 0 {
+	} else if m.SelectedID > 0 && getSelectedEntryIndex(m.Entries, m.SelectedID) >= 0 {
 		return entryDetailView(m)
+	} else if m.CurrentView == "logs" {
+		return logsView(m)
+	} else if m.CurrentView == "tags" {
+		return tagsView(m)
+	} else if m.CurrentView == "profiles" {
+		return m.ProfilesTable.View()
+	} else if m.CurrentView == "grouped" {
+		return groupedView(m)
 	}
 	return listView(m)
 }
 
+// Regenerate the content of the entry being read, keeping the scroll position.
+func refreshDetailViewport(m *model) {
+	yOffset := m.Viewport.YOffset
+	setDetailViewportContent(m)
+	m.Viewport.SetYOffset(yOffset)
+}
+
+// Set the content of the entry being read in the viewport.
+// Matches of the search in the article are located again, lines depend on wrapping.
+func setDetailViewportContent(m *model) {
+	_, wrapWidth := getReadingWidths(m.ReadingWidth, m.TermSize.Width)
+	content := getDetailViewportContent(m.SelectedID, m.Entries, m.ContentCache, walgotDetailOptions{
+		walgotContentOptions: walgotContentOptions{
+			WrapWidth:      wrapWidth,
+			Renderer:       m.ContentRenderer,
+			LinkReferences: !m.NoLinkReferences,
+			Trim:           m.TrimRules,
+		},
+		Metadata:   m.ShowMetadata,
+		DateFormat: m.DateFormat,
+		Highlight:  getHighlightTerm(m.ArticleSearch.Term, m.Options.Filters),
+		Theme:      m.Theme,
+	})
+	m.Viewport.SetContent(content)
+	// Title takes more lines with a long title or tags:
+	if index := getSelectedEntryIndex(m.Entries, m.SelectedID); index >= 0 {
+		h := m.TermSize.Height - lipgloss.Height(m.headerView()) - lipgloss.Height(m.footerView())
+		m.Viewport.Height = getMainHeight(h, lipgloss.Height(getDetailViewTitle(m, &m.Entries[index]))+1)
+	}
+	m.ArticleSearch.Lines = getMatchLines(content, m.ArticleSearch.Term)
+	if m.ArticleSearch.Current >= len(m.ArticleSearch.Lines) {
+		m.ArticleSearch.Current = 0
+	}
+}
+
 // Manage window size changes.
 func windowSizeUpdate(m *model) {
 	h := m.TermSize.Height - lipgloss.Height(m.headerView()) - lipgloss.Height(m.footerView())
-	// Regenerate the table based on new size:
-	t := createViewTable(m.TermSize.Width, h-5)
+	// Regenerate the table based on new size, rows are set on the new table
+	// and the cursor is kept on the same entry:
+	cursorID, cursor := getSelectedRowID(m.Table, m.NbFilteredEntries), m.Table.Cursor()
+	m.Table = createViewTable(getListWidth(m.ListWidth, m.TermSize.Width, m.ShowPreview), getMainHeight(h, 5), m.Columns, m.CompactList, m.Theme)
 	if m.Ready {
-		m.Table.SetRows(getTableRows(m.Entries, m.Options.Filters, m.TermSize.Width))
+		setTableRows(m, cursorID, cursor)
+	}
+	if m.CurrentView == "tags" {
+		setTagsTable(m, m.TagsTable.Cursor())
+	}
+	if m.CurrentView == "profiles" {
+		setProfilesTable(m)
+	}
+	m.LogsViewport = viewport.New(m.TermSize.Width, getMainHeight(h, 2))
+	if m.CurrentView == "logs" {
+		setLogsViewport(m)
 	}
-	m.Table = t
 	// Generate viewport based on screen size
-	contentWidth := 80
-	if m.TermSize.Width < 80 {
-		contentWidth = m.TermSize.Width
+	contentWidth, _ := getReadingWidths(m.ReadingWidth, m.TermSize.Width)
+	yOffset := m.Viewport.YOffset
+	m.Viewport = viewport.New(contentWidth, getMainHeight(h, 5))
+	// Article being read is wrapped again for the new size:
+	if m.SelectedID > 0 {
+		setDetailViewportContent(m)
+		m.Viewport.SetYOffset(yOffset)
 	}
-	v := viewport.New(contentWidth, h-5)
 
 	// We recieved terminal size, we are ready:
 	m.Ready = true
-	// Saving viewport in model:
-	m.Viewport = v
 }
 
 // Manage reloading view.
 func reloadingView(m model) string {
-	text := "Loading all"
-	if m.TotalEntriesOnServer > 0 {
-		text += " " + strconv.Itoa(m.TotalEntriesOnServer)
+	if m.Paginated {
+		return lipgloss.NewStyle().
+			Width(m.TermSize.Width).
+			Align(lipgloss.Center).
+			Render(m.Spinner.View() + "Loading entries from wallabag…\n\n(esc to cancel)")
 	}
-	text += " entries from wallabag…"
+
+	// Counting up while pages are retrieved, spinner keeps it refreshed:
+	text := getLoadingText(m.TotalEntriesOnServer, m.LoadedEntries)
 	if m.TotalEntriesOnServer > 250 {
 		text += " (This can take a few moment…)"
 	}
 
+	// Progress bar, only once entries are being retrieved:
+	bar := ""
+	if m.TotalEntriesOnServer > 0 {
+		p := m.Progress
+		p.Width = 60
+		if m.TermSize.Width-4 < p.Width {
+			p.Width = m.TermSize.Width - 4
+		}
+		bar = "\n\n" + p.ViewAs(m.LoadProgress)
+	}
+
 	return lipgloss.NewStyle().
 		Width(m.TermSize.Width).
 		Align(lipgloss.Center).
-		Render(m.Spinner.View() + text)
+		Render(m.Spinner.View() + text + bar + "\n\n(esc to cancel)")
 }
 
-// Help view.
+// Help view, generated from the keybindings registry.
 func helpView(m model) string {
-	text := []byte(`Help:
-  On all screens:
-  - ctrl+c: Quit
-  - h: Help (this page)
-
-  On listing page:
-  - r: Reload article from wallabag via APIs, takes time depending on the number of articles saved
-  - u: Toggle display only unread articles (disable archived filter)
-  - s: Toggle display only starred articles
-  - a: Toggle archived only articles (disable unread filter)
-  - p: Toggle public only articles (articles with a public link)
-  - A: Toggle Archive / Unread for the current article (and update wallabag backend)
-  - S: Toggle Starred / Unstarred for the current article (and update wallabag backend)
-  - P: Toggle Public status - Public means article can be shared with a public link
-  - O: Open article public link url in default browser. If article isn't public, it will open the original article link.
-  - Y: Yank (copy) URL to clipboard. If article isn't public, it will open the original article link.
-  - /: Open search box
-  - N: Add a new url to wallabag.
-  - D: Delete the selected entry.
-  - esc: Clean search filter, if any
-  - h: Display help
-  - ↑ or k / ↓ or j: Move up / down one item in the list
-  - page down / page up: Move up / down 10 items in the list
-  - home: Go to the top of the list
-  - end: Go to bottom of the list
-  - enter: Select entry to read content
-  - q: Remove search filter if any, otherwise quit
-
-  On detail page:
-  - A: Toggle Archive / Unread for the current article (and update wallabag backend)
-  - S: Toggle Starred / Unstarred for the current article (and update wallabag backend)
-  - P: Toggle Public status - Public means article can be shared with a public link
-  - O: Open article public link url in default browser. If article isn't public, it will open the original article link.
-  - Y: Yank (copy) URL to clipboard. If article isn't public, it will open the original article link.
-  - L: Open link within content. Give a link number as displayed in footnotes of the article.
-  - D: Delete the selected entry.
-  - q: Return to list
-  - ↑ or k / ↓ or j: Go up / down
-
-  On any dialog (modal) view:
-  - "esc": Close the dialog
-
-  On search modal view:
-  - "enter": start search
-
-
-  On help page:
-  - q, esc: Return to list
-
-  Status explanation:
-  - ⭐ Starred article
-  - 🆕 Unread article
-  - 🔗 Article with a public shareable link
-`)
+	titleStyle := lipgloss.NewStyle().Bold(true).MarginTop(1)
+	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.Theme["accent"])).PaddingRight(2)
+
+	// Keys column is as large as the largest keys list:
+	keysWidth := 0
+	for _, group := range keybindingsHelp {
+		for _, entry := range group.Entries {
+			if w := lipgloss.Width(strings.Join(entry.getKeys(m.Keys), ", ")); w > keysWidth {
+				keysWidth = w
+			}
+		}
+	}
+	keyStyle = keyStyle.Width(keysWidth + 2)
+	descriptionWidth := m.TermSize.Width - keysWidth - 4
+	if descriptionWidth < 10 {
+		descriptionWidth = 10
+	}
+	descriptionStyle := lipgloss.NewStyle().Width(descriptionWidth)
+
+	sections := []string{lipgloss.NewStyle().Bold(true).Render("Help:")}
+	for _, group := range keybindingsHelp {
+		sections = append(sections, titleStyle.Render(group.Title))
+		for _, entry := range group.Entries {
+			sections = append(sections, lipgloss.JoinHorizontal(
+				lipgloss.Top,
+				keyStyle.Render(strings.Join(entry.getKeys(m.Keys), ", ")),
+				descriptionStyle.Render(entry.Description),
+			))
+		}
+	}
+
+	sections = append(sections,
+		titleStyle.Render("Status explanation"),
+		"⭐ Starred article",
+		"🆕 Unread article",
+		"🔗 Article with a public shareable link",
+	)
 
 	return lipgloss.
 		NewStyle().
 		Width(m.TermSize.Width).
+		PaddingLeft(2).
 		Align(lipgloss.Left).
-		Render(string(text))
+		Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
 }
 
 // Get article detail view.
 func entryDetailView(m model) string {
 	i := getSelectedEntryIndex(m.Entries, m.SelectedID)
-	header := entryDetailViewTitle(&m.Entries[i], m.TermSize.Width)
-	footer := entryDetailViewFooter(m.Viewport, &m.Entries[i])
+	if i < 0 {
+		return listView(m)
+	}
+	header := getDetailViewTitle(&m, &m.Entries[i])
+	footer := entryDetailViewFooter(m.Viewport, &m.Entries[i], getArticleSearchText(m.ArticleSearch))
+	content := m.Viewport.View()
+	// Only the article is replaced while it is refreshed:
+	if m.RefreshingID == m.SelectedID {
+		content = lipgloss.Place(
+			m.Viewport.Width,
+			m.Viewport.Height,
+			lipgloss.Center,
+			lipgloss.Center,
+			m.Spinner.View()+"Refreshing article from wallabag…",
+		)
+	}
 
 	return lipgloss.
 		NewStyle().
 		Width(m.TermSize.Width).
 		Align(lipgloss.Center).
-		Render(header + "\n" + m.Viewport.View() + "\n" + footer)
+		Render(header + "\n" + content + "\n" + footer)
 }
 
-// Retrieve title for detail view.
-func entryDetailViewTitle(entry *wallabago.Item, maxWidth int) string {
-	w := 80
-	if maxWidth < 80 {
+// Retrieve title of the entry being read, with its tags unless the metadata
+// panel (including them) is displayed.
+func getDetailViewTitle(m *model, entry *wallabago.Item) string {
+	tags := ""
+	if !m.ShowMetadata {
+		tags = entryDetailViewTags(entry, m.ShowEmptyTags)
+	}
+
+	return entryDetailViewTitle(entry, m.TermSize.Width, m.Viewport.Width, tags)
+}
+
+// Retrieve title for detail view, tags are displayed under the title if any.
+func entryDetailViewTitle(entry *wallabago.Item, maxWidth, readingWidth int, tags string) string {
+	w := readingWidth
+	if maxWidth < w+4 {
 		w = maxWidth - 4
 	}
 	title := lipgloss.
@@ -239,6 +389,21 @@ func entryDetailViewTitle(entry *wallabago.Item, maxWidth int) string {
 		Width(w).
 		Align(lipgloss.Center).
 		Render(wordwrap.String(entry.Title, w-8))
+	readingTime := lipgloss.
+		NewStyle().
+		Faint(true).
+		Width(w).
+		Align(lipgloss.Center).
+		Render("Est. read: " + formatReadingTime(entry.ReadingTime))
+	title = lipgloss.JoinVertical(lipgloss.Center, title, readingTime)
+	if tags != "" {
+		tags = lipgloss.
+			NewStyle().
+			Width(w).
+			Align(lipgloss.Center).
+			Render(wordwrap.String(tags, w-8))
+		title = lipgloss.JoinVertical(lipgloss.Center, title, tags)
+	}
 
 	return lipgloss.
 		NewStyle().
@@ -246,8 +411,8 @@ func entryDetailViewTitle(entry *wallabago.Item, maxWidth int) string {
 		Render(title)
 }
 
-// Retrieve footer for detail view.
-func entryDetailViewFooter(viewport viewport.Model, entry *wallabago.Item) string {
+// Retrieve footer for detail view, with the matches of the search in the article if any.
+func entryDetailViewFooter(viewport viewport.Model, entry *wallabago.Item, searchText string) string {
 	status := ""
 	if entry.IsArchived == 0 {
 		status += "🆕"
@@ -272,26 +437,185 @@ func entryDetailViewFooter(viewport viewport.Model, entry *wallabago.Item) strin
 		BorderLeft(true).
 		BorderRight(true).
 		Render(fmt.Sprintf("%3.f%%", viewport.ScrollPercent()*100))
+	if searchText != "" {
+		readInfo = lipgloss.
+			NewStyle().
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderRight(true).
+			Render(searchText) + readInfo
+	}
 
 	width := viewport.Width - lipgloss.Width(readInfo) - lipgloss.Width(statusInfo)
 	if width < 0 {
 		width = 0
 	}
-	line := strings.Repeat("─", width)
+	line := getReadProgressLine(width, viewport.ScrollPercent())
 
 	return lipgloss.JoinHorizontal(lipgloss.Center, statusInfo, line, readInfo)
 }
 
-// Get list view.
+// Get list view, with the preview of the selected entry on wide terminals.
 func listView(m model) string {
-	return m.Table.View()
+	if !m.Reloading && m.NbFilteredEntries == 0 {
+		return emptyListView(m)
+	}
+	table := m.Table.View()
+	width := getPreviewWidth(m.ListWidth, m.TermSize.Width, m.ShowPreview)
+	if width == 0 {
+		return table
+	}
+
+	preview := ""
+	if index := getSelectedEntryIndex(m.Entries, getSelectedRowID(m.Table, m.NbFilteredEntries)); index >= 0 {
+		// Border and padding on the left:
+		preview = getPreviewText(&m.Entries[index], width-3, lipgloss.Height(table))
+	}
+
+	return lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		table,
+		lipgloss.
+			NewStyle().
+			Width(width-1).
+			Height(lipgloss.Height(table)).
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderForeground(lipgloss.Color(m.Theme["headerBorder"])).
+			BorderLeft(true).
+			PaddingLeft(1).
+			Render(preview),
+	)
+}
+
+// Get list view when there is no article to display.
+func emptyListView(m model) string {
+	text := "No articles in wallabag yet, add one with " + m.Keys["add"]
+	if m.ReloadCtx != nil && m.ReloadCtx.Err() != nil {
+		text = "Loading has been canceled, reload with " + m.Keys["reload"]
+	}
+	if len(m.Entries) > 0 {
+		text = "No articles match the current filters"
+		if filters := getActiveFilters(m.Options.Filters); len(filters) > 0 {
+			text += ":\n" + strings.Join(filters, ", ")
+		}
+	}
+
+	return lipgloss.
+		NewStyle().
+		Width(m.TermSize.Width).
+		Align(lipgloss.Center).
+		PaddingTop(2).
+		Faint(true).
+		Render(text)
+}
+
+// Get tags view, or a message if no loaded article has tags.
+func tagsView(m model) string {
+	if m.TagsTable.Cursor() >= 0 {
+		return m.TagsTable.View()
+	}
+
+	return lipgloss.
+		NewStyle().
+		Width(m.TermSize.Width).
+		Align(lipgloss.Center).
+		PaddingTop(2).
+		Faint(true).
+		Render("No tags on loaded articles")
+}
+
+// Get logs view, with the last log lines.
+func logsView(m model) string {
+	if len(m.Logs.getLines()) == 0 {
+		return lipgloss.
+			NewStyle().
+			Width(m.TermSize.Width).
+			Align(lipgloss.Center).
+			PaddingTop(2).
+			Faint(true).
+			Render("No log messages yet")
+	}
+
+	return m.LogsViewport.View()
+}
+
+// Get grouped by domain view, the part of the rows around the cursor is displayed.
+func groupedView(m model) string {
+	rows := getGroupedRows(getDomainGroups(m.Entries, m.Options.Filters), m.ExpandedDomains)
+	if len(rows) == 0 {
+		return emptyListView(m)
+	}
+
+	selectedStyle := lipgloss.
+		NewStyle().
+		Foreground(lipgloss.Color(m.Theme["selectedForeground"])).
+		Background(lipgloss.Color(m.Theme["selectedBackground"]))
+	headerStyle := lipgloss.NewStyle().Bold(true)
+	width := getListWidth(m.ListWidth, m.TermSize.Width, false)
+
+	start := 0
+	if m.GroupedCursor >= m.Viewport.Height {
+		start = m.GroupedCursor - m.Viewport.Height + 1
+	}
+	lines := []string{}
+	for i := start; i < len(rows) && i < start+m.Viewport.Height; i++ {
+		line := truncate.StringWithTail(rows[i].text(), uint(width), "…")
+		if i == m.GroupedCursor {
+			line = selectedStyle.Render(line)
+		} else if rows[i].ID == 0 {
+			line = headerStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Get list view with search input on top.
+func searchView(m *model) string {
+	m.Dialog.TextInput.PromptStyle = lipgloss.
+		NewStyle().
+		Foreground(lipgloss.Color(m.Theme["accent"]))
+
+	return m.Dialog.TextInput.View() + "\n" + listView(*m)
+}
+
+// Get keys popup, with the keys of the current view.
+func (m model) keysPopupView() string {
+	view := m.CurrentView
+	if m.SelectedID > 0 {
+		view = "detail"
+	}
+	// Popup takes at most half of the main view, borders included:
+	height := m.TermSize.Height - lipgloss.Height(m.headerView()) - lipgloss.Height(m.footerView())
+	width := m.TermSize.Width - 2
+	text := getKeysPopupText(getViewKeysHelp(view, m.Keys), width, height/2-3)
+	title := lipgloss.NewStyle().Bold(true).Render("Keys") +
+		lipgloss.NewStyle().Faint(true).Render(" (any key closes it, "+m.Keys["help"]+" for full help)")
+
+	return lipgloss.
+		NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(m.Theme["dialogBorder"])).
+		Width(width).
+		Render(title + "\n" + text)
+}
+
+// Replace the last lines of view with popup, which stays at the bottom of the screen.
+func overlayBottom(view, popup string) string {
+	lines := strings.Split(view, "\n")
+	popupLines := strings.Split(popup, "\n")
+	if len(popupLines) >= len(lines) {
+		return popup
+	}
+
+	return strings.Join(append(lines[:len(lines)-len(popupLines)], popupLines...), "\n")
 }
 
 // Get dialog view.
 func dialogView(m *model) string {
 	dialogBoxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#874BFD")).
+		BorderForeground(lipgloss.Color(m.Theme["dialogBorder"])).
 		Padding(1, 0).
 		BorderTop(true).
 		BorderLeft(true).
@@ -299,7 +623,7 @@ func dialogView(m *model) string {
 		BorderBottom(true)
 
 	actionButton := ""
-	if m.Dialog.Action == "search" || m.Dialog.Action == "add" || m.Dialog.Action == "open link" {
+	if m.Dialog.Action != "" {
 		text := strings.Title(m.Dialog.Action) + " (Enter)"
 		actionButton = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FFF7DB")).
@@ -333,7 +657,7 @@ func dialogView(m *model) string {
 	if m.Dialog.ShowInput {
 		m.Dialog.TextInput.PromptStyle = lipgloss.
 			NewStyle().
-			Foreground(lipgloss.Color("205")).
+			Foreground(lipgloss.Color(m.Theme["accent"])).
 			Align(lipgloss.Left)
 		content = lipgloss.JoinVertical(
 			lipgloss.Left,
@@ -352,66 +676,74 @@ func dialogView(m *model) string {
 }
 
 // ** Table related functions ** //
-// Create Columns.
-func createViewTableColumns(maxWidth int) []table.Column {
-	baseWidth := int(maxWidth / 20)
-	var columns []table.Column
-
+// Retrieve the columns to display, depending on screen width.
+// Configured columns are displayed on wide screens only.
+// ID column must always be first as it is used for selecting entries.
+func getTableColumnNames(maxWidth int, columns []string) []string {
 	if maxWidth > 130 {
-		columns = []table.Column{
-			{Title: "ID", Width: baseWidth},
-			{Title: "Status", Width: baseWidth},
-			{Title: "Title", Width: baseWidth * 12},
-			{Title: "Domain", Width: baseWidth * 4},
-			{Title: "Created", Width: baseWidth * 2},
-		}
+		return columns
 	} else if maxWidth > 80 {
-		columns = []table.Column{
-			{Title: "ID", Width: baseWidth},
-			{Title: "Status", Width: baseWidth},
-			{Title: "Title", Width: baseWidth * 18},
-		}
-	} else {
-		columns = []table.Column{
-			{Title: "ID", Width: 0},
-			{Title: "Title", Width: baseWidth * 20},
-		}
+		return []string{"ID", "Status", "Title"}
+	}
+
+	return []string{"ID", "Title"}
+}
+
+// Create Columns.
+func createViewTableColumns(maxWidth int, columns []string) []table.Column {
+	// Number of baseWidth per column, title takes the remaining space:
+	columnsWidth := map[string]int{
+		"ID":        1,
+		"Status":    1,
+		"Starred":   1,
+		"Archived":  1,
+		"Excerpt":   4,
+		"Domain":    3,
+		"Tags":      3,
+		"Est. read": 2,
+		"Created":   2,
+		"Updated":   2,
+	}
 
+	names := getTableColumnNames(maxWidth, columns)
+	titleWidth := 20
+	for _, name := range names {
+		titleWidth -= columnsWidth[name]
 	}
+	// With many columns, they are narrower to keep the title readable:
+	baseWidth := int(maxWidth / 20)
+	if titleWidth < 4 {
+		baseWidth = maxWidth / (20 - titleWidth + 4)
+		titleWidth = 4
+	}
+	// On small screen, ID is hidden:
+	if maxWidth <= 80 {
+		columnsWidth["ID"] = 0
+		titleWidth = 20
+	}
+	columnsWidth["Title"] = titleWidth
 
-	return columns
+	var tableColumns []table.Column
+	for _, name := range names {
+		tableColumns = append(tableColumns, table.Column{Title: name, Width: baseWidth * columnsWidth[name]})
+	}
+
+	return tableColumns
 }
 
 // Create rows
-// TODO: create test for this function.
-func getTableRows(items []wallabago.Item, filters walgotTableFilters, maxWidth int) []table.Row {
+// Excerpts are cached by entry ID, they are computed only if the excerpt column is displayed.
+func getTableRows(items []wallabago.Item, filters walgotTableFilters, options walgotRowsOptions) []table.Row {
 	r := []table.Row{}
+	names := getTableColumnNames(options.MaxWidth, options.Columns)
+	excerpts := options.Excerpts
+	showExcerpt := excerpts != nil && hasTableColumn(names, "Excerpt")
 
 	for i := 0; i < len(items); i++ {
 		title := items[i].Title
-		id := strconv.Itoa(items[i].ID)
-		domainName := items[i].DomainName
 		status := "  "
-		createdAt := items[i].CreatedAt.Time.Format("2006-02-01")
 
-		// Public filter:
-		if filters.Public && !items[i].IsPublic {
-			continue
-		}
-		// Unread filter:
-		if filters.Unread && items[i].IsArchived != 0 {
-			continue
-		}
-		// Archived filter:
-		if filters.Archived && items[i].IsArchived != 1 {
-			continue
-		}
-		// Starred filter:
-		if filters.Starred && items[i].IsStarred != 1 {
-			continue
-		}
-		// Search filter:
-		if filters.Search != "" && !containsI(items[i].Title, filters.Search) {
+		if !isEntryMatchingFilters(&items[i], filters) {
 			continue
 		}
 
@@ -429,35 +761,44 @@ func getTableRows(items []wallabago.Item, filters walgotTableFilters, maxWidth i
 			status += "🔗"
 		}
 
-		if archivedEntry {
+		// Compact list has no styling:
+		if archivedEntry && !options.Compact {
 			// This create a bug in the selected row,
 			// where it stops the selected style (blue background).
 			// TODO: Create an issue on bubble bugtracker
 			title = lipgloss.NewStyle().Faint(true).Render(title)
 		}
+		// Entries selected for batch actions:
+		if options.Marked[items[i].ID] {
+			title = "● " + title
+		}
 
-		var new table.Row
-		if maxWidth > 130 {
-			new = table.Row{
-				id,
-				status,
-				title,
-				domainName,
-				createdAt,
-			}
-		} else if maxWidth > 80 {
-			new = table.Row{
-				id,
-				status,
-				title,
-			}
-		} else {
-			new = table.Row{
-				id,
-				title,
+		// Converting content is expensive, it is only done once per entry:
+		if showExcerpt {
+			if _, ok := excerpts[items[i].ID]; !ok {
+				excerpts[items[i].ID] = getExcerpt(items[i].Content, excerptLength)
 			}
 		}
 
+		values := map[string]string{
+			"ID":        strconv.Itoa(items[i].ID),
+			"Status":    status,
+			"Starred":   getFlagLabel(items[i].IsStarred == 1, "⭐"),
+			"Archived":  getFlagLabel(items[i].IsArchived == 1, "✓"),
+			"Title":     title,
+			"Excerpt":   excerpts[items[i].ID],
+			"Domain":    items[i].DomainName,
+			"Tags":      strings.Join(getEntryTagLabels(&items[i]), ", "),
+			"Est. read": formatReadingTime(items[i].ReadingTime),
+			"Created":   formatEntryDate(items[i].CreatedAt, options.DateFormat, options.RelativeDates),
+			"Updated":   formatEntryDate(items[i].UpdatedAt, options.DateFormat, options.RelativeDates),
+		}
+
+		new := table.Row{}
+		for _, name := range names {
+			new = append(new, values[name])
+		}
+
 		r = append(r, new)
 	}
 
@@ -465,34 +806,153 @@ func getTableRows(items []wallabago.Item, filters walgotTableFilters, maxWidth i
 }
 
 // Generate the bubbletea table.
-func createViewTable(maxWidth int, maxHeight int) table.Model {
+// Compact table has a header without borders, leaving more lines for rows.
+func createViewTable(maxWidth int, maxHeight int, columns []string, compact bool, theme walgotTheme) table.Model {
+	t := table.New(
+		table.WithColumns(createViewTableColumns(maxWidth, columns)),
+		table.WithHeight(maxHeight+getTableHeaderHeight(false)-getTableHeaderHeight(compact)),
+	)
+	t.SetStyles(getTableStyles(theme, compact))
+	// There is no row yet, the cursor must not point to one:
+	t.SetCursor(0)
+
+	return t
+}
+
+// Generate the tags table, with the number of articles per tag.
+func createTagsTable(tags []walgotTagCount, maxWidth int, maxHeight int, compact bool, theme walgotTheme) table.Model {
+	countWidth := 10
+	rows := []table.Row{}
+	for _, tag := range tags {
+		rows = append(rows, table.Row{tag.Label, strconv.Itoa(tag.Count)})
+	}
+	t := table.New(
+		// Cells are padded by one space on each side:
+		table.WithColumns([]table.Column{
+			{Title: "Tag", Width: maxWidth - countWidth - 4},
+			{Title: "Articles", Width: countWidth},
+		}),
+		table.WithHeight(maxHeight),
+		table.WithRows(rows),
+	)
+	t.SetStyles(getTableStyles(theme, compact))
+	// Cursor must not point to a row if there is none:
+	t.SetCursor(0)
+
+	return t
+}
+
+// Generate the profiles table, the active profile is marked.
+func createProfilesTable(profiles []string, active string, maxWidth int, maxHeight int, compact bool, theme walgotTheme) table.Model {
+	activeWidth := 10
+	rows := []table.Row{}
+	for _, p := range profiles {
+		status := ""
+		if p == active {
+			status = "✓"
+		}
+		rows = append(rows, table.Row{getProfileLabel(p), status})
+	}
 	t := table.New(
-		table.WithColumns(createViewTableColumns(maxWidth)),
+		// Cells are padded by one space on each side:
+		table.WithColumns([]table.Column{
+			{Title: "Profile", Width: maxWidth - activeWidth - 4},
+			{Title: "Active", Width: activeWidth},
+		}),
 		table.WithHeight(maxHeight),
+		table.WithRows(rows),
 	)
+	t.SetStyles(getTableStyles(theme, compact))
+
+	return t
+}
+
+// Return the styles of walgot tables.
+func getTableStyles(theme walgotTheme, compact bool) table.Styles {
 	s := table.DefaultStyles()
-	s.Header = s.Header.
-		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("240")).
-		BorderBottom(true).
-		BorderTop(true).
-		Bold(true)
+	s.Header = s.Header.Bold(true)
+	if !compact {
+		s.Header = s.Header.
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderForeground(lipgloss.Color(theme["headerBorder"])).
+			BorderBottom(true).
+			BorderTop(true)
+	}
 	s.Selected = s.Selected.
-		Foreground(lipgloss.Color("229")).
-		Background(lipgloss.Color("57"))
+		Foreground(lipgloss.Color(theme["selectedForeground"])).
+		Background(lipgloss.Color(theme["selectedBackground"]))
 
-	t.SetStyles(s)
+	return s
+}
 
-	return t
+// Number of lines of table headers, borders included.
+func getTableHeaderHeight(compact bool) int {
+	if compact {
+		return 1
+	}
+
+	return 3
 }
 
 // ** Viewport related functions ** //
 // Generate content for article detail viewport.
-func getDetailViewportContent(selectedID int, entries []wallabago.Item, maxWidth int) string {
+// Converted article content is kept in cache, to be displayed again quickly.
+// With metadata, the metadata panel is displayed above the article.
+func getDetailViewportContent(selectedID int, entries []wallabago.Item, cache *walgotContentCache, options walgotDetailOptions) string {
 	content := "…"
 	if index := getSelectedEntryIndex(entries, selectedID); index >= 0 {
-		content = getSelectedEntryContent(entries, index, maxWidth)
+		wrapWidth := options.WrapWidth
+		var ok bool
+		if content, ok = cache.get(selectedID, wrapWidth); !ok {
+			content = getSelectedEntryContent(&entries[index], options.walgotContentOptions)
+			cache.set(selectedID, wrapWidth, content)
+		}
+		// Search term is highlighted after wrapping, it doesn't change lines width:
+		highlightStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(options.Theme["highlightForeground"])).
+			Background(lipgloss.Color(options.Theme["highlightBackground"]))
+		content = highlightMatches(content, options.Highlight, func(s string) string {
+			return highlightStyle.Render(s)
+		})
+		if annotations := getAnnotationsText(entries[index].Annotations, wrapWidth); annotations != "" {
+			content += "\n\n" + annotations
+		}
+		if options.Metadata {
+			content = entryDetailViewMetadata(&entries[index], wrapWidth, options.DateFormat, options.Theme) + "\n\n" + content
+		}
 	}
 
 	return content
 }
+
+// Retrieve metadata panel for detail view.
+func entryDetailViewMetadata(entry *wallabago.Item, wrapWidth int, dateFormat string, theme walgotTheme) string {
+	// Borders and padding are within the reading width:
+	width := wrapWidth - 4
+	if width < 1 {
+		width = 1
+	}
+
+	return lipgloss.
+		NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme["headerBorder"])).
+		Padding(0, 1).
+		Width(width).
+		Render(getEntryMetadataText(entry, dateFormat, wallabago.Config.WallabagURL))
+}
+
+// Retrieve tags line for detail view.
+func entryDetailViewTags(entry *wallabago.Item, showEmptyTags bool) string {
+	tags := "none"
+	if labels := getEntryTagLabels(entry); len(labels) > 0 {
+		tags = strings.Join(labels, ", ")
+	} else if !showEmptyTags {
+		return ""
+	}
+
+	return lipgloss.
+		NewStyle().
+		Italic(true).
+		Render("Tags: " + tags)
+}
@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Strubbl/wallabago/v7"
+	"github.com/charmbracelet/bubbles/table"
+)
+
+func TestGetTableRows(t *testing.T) {
+	entries := []wallabago.Item{
+		{ID: 1, Title: "One", IsStarred: 1, DomainName: "example.com"},
+		{ID: 2, Title: "Two", IsArchived: 1, DomainName: "example.org"},
+		{ID: 3, Title: "Three", IsPublic: true, DomainName: "example.com"},
+	}
+	columns := []string{"ID", "Starred", "Archived", "Title", "Domain"}
+	var tests = []struct {
+		inputFilters walgotTableFilters
+		inputOptions walgotRowsOptions
+		expected     []table.Row
+	}{
+		// Narrow list has ID and title only:
+		{walgotTableFilters{}, walgotRowsOptions{MaxWidth: 60, Compact: true}, []table.Row{{"1", "One"}, {"2", "Two"}, {"3", "Three"}}},
+		// Status with flags:
+		{walgotTableFilters{ReadState: "unread"}, walgotRowsOptions{MaxWidth: 100, Compact: true}, []table.Row{{"1", "🆕⭐", "One"}, {"3", "🆕  🔗", "Three"}}},
+		// Configured columns on wide list, selected entries are marked:
+		{walgotTableFilters{Search: "example.com"}, walgotRowsOptions{MaxWidth: 150, Columns: columns, Compact: true, Marked: map[int]bool{3: true}}, []table.Row{{"1", "⭐", "", "One", "example.com"}, {"3", "", "", "● Three", "example.com"}}},
+		{walgotTableFilters{Starred: true, ReadState: "archived"}, walgotRowsOptions{MaxWidth: 60}, []table.Row{}},
+	}
+
+	for _, test := range tests {
+		if result := getTableRows(entries, test.inputFilters, test.inputOptions); !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("getTableRows(%v, %v): expected %q, got %q", test.inputFilters, test.inputOptions, test.expected, result)
+		}
+	}
+}
+
+func TestEntryDetailViewTitle(t *testing.T) {
+	entry := wallabago.Item{Title: "Title", Tags: []wallabago.Tag{{Label: "go"}, {Label: "tui"}}}
+	var tests = []struct {
+		inputTags      string
+		expectedTags   bool
+		expectedHeight int
+	}{
+		{entryDetailViewTags(&entry, false), true, 5},
+		{"", false, 4},
+	}
+
+	for _, test := range tests {
+		result := entryDetailViewTitle(&entry, 100, 80, test.inputTags)
+		if strings.Contains(result, "Tags: go, tui") != test.expectedTags {
+			t.Errorf("entryDetailViewTitle(%q): expectedTags %v, got %q", test.inputTags, test.expectedTags, result)
+		}
+		if height := strings.Count(result, "\n") + 1; height != test.expectedHeight {
+			t.Errorf("entryDetailViewTitle(%q): expectedHeight %v, got %v", test.inputTags, test.expectedHeight, height)
+		}
+	}
+}
+
+func TestGetDetailViewportContentWithoutTags(t *testing.T) {
+	entries := []wallabago.Item{{ID: 1, Content: "<p>Article</p>", Tags: []wallabago.Tag{{Label: "go"}}}}
+	content := getDetailViewportContent(1, entries, newContentCache(10), walgotDetailOptions{
+		walgotContentOptions: walgotContentOptions{WrapWidth: 80},
+		Theme:                defaultTheme,
+	})
+	// Tags are under the title, not in the scrolled article:
+	if strings.Contains(content, "Tags:") || !strings.Contains(content, "Article") {
+		t.Errorf("getDetailViewportContent: expected article without tags, got %q", content)
+	}
+}
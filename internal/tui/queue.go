@@ -0,0 +1,175 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"git.bacardi55.io/bacardi55/walgot/internal/api"
+	"github.com/Strubbl/wallabago/v7"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A change of an entry not sent to wallabag yet, offline or when wallabag
+// couldn't be reached. Archive, starred and public are the wanted values.
+type walgotPendingChange struct {
+	ID       int
+	Delete   bool
+	Archive  int
+	Starred  int
+	Public   int
+	QueuedAt time.Time
+}
+
+// Message for a change to queue, with the error of the failed call if any.
+type walgotChangeQueuedMsg struct {
+	Change walgotPendingChange
+	Err    error
+}
+
+// Message for sent pending changes.
+// Done changes are removed from the queue, sent or not: entries changed on
+// wallabag after the change was queued are conflicts, wallabag version is kept.
+type walgotChangesSentMsg struct {
+	QueueFile string
+	Done      []walgotPendingChange
+	Sent      int
+	Conflicts []wallabago.Item
+}
+
+// Load pending changes from file, there is none without file.
+func loadPendingChanges(queueFile string) ([]walgotPendingChange, error) {
+	var changes []walgotPendingChange
+
+	raw, err := os.ReadFile(queueFile)
+	if os.IsNotExist(err) {
+		return changes, nil
+	} else if err != nil {
+		return changes, err
+	}
+	err = json.Unmarshal(raw, &changes)
+
+	return changes, err
+}
+
+// Save pending changes in file, the file is removed when there is none left.
+func savePendingChanges(queueFile string, changes []walgotPendingChange) error {
+	if len(changes) == 0 {
+		if err := os.Remove(queueFile); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	raw, err := json.MarshalIndent(changes, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(queueFile), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(queueFile, raw, 0600)
+}
+
+// Add a change to the queue, it replaces the previous change of the same entry.
+func queueChange(changes []walgotPendingChange, change walgotPendingChange) []walgotPendingChange {
+	queued := []walgotPendingChange{}
+	for _, c := range changes {
+		if c.ID != change.ID {
+			queued = append(queued, c)
+		}
+	}
+
+	return append(queued, change)
+}
+
+// Remove done changes from the queue, changes queued since are kept.
+func removeChanges(changes []walgotPendingChange, done []walgotPendingChange) []walgotPendingChange {
+	remaining := []walgotPendingChange{}
+	for _, c := range changes {
+		isDone := false
+		for _, d := range done {
+			isDone = isDone || (c.ID == d.ID && c.QueuedAt.Equal(d.QueuedAt))
+		}
+		if !isDone {
+			remaining = append(remaining, c)
+		}
+	}
+
+	return remaining
+}
+
+// Apply pending changes to entries, as if they were already sent to wallabag.
+func applyPendingChanges(entries []wallabago.Item, changes []walgotPendingChange) []wallabago.Item {
+	if len(changes) == 0 {
+		return entries
+	}
+	byID := map[int]walgotPendingChange{}
+	for _, c := range changes {
+		byID[c.ID] = c
+	}
+
+	applied := []wallabago.Item{}
+	for _, e := range entries {
+		c, ok := byID[e.ID]
+		if ok && c.Delete {
+			continue
+		}
+		if ok {
+			e.IsArchived = c.Archive
+			e.IsStarred = c.Starred
+			e.IsPublic = c.Public == 1
+		}
+		applied = append(applied, e)
+	}
+
+	return applied
+}
+
+// Check if the entry has been changed on wallabag after the change was queued.
+func isConflictingChange(change walgotPendingChange, entry wallabago.Item) bool {
+	return entry.UpdatedAt != nil && entry.UpdatedAt.Time.After(change.QueuedAt)
+}
+
+// Callback for queuing a change instead of sending it, offline.
+func queueChangeCommand(change walgotPendingChange) tea.Cmd {
+	return func() tea.Msg {
+		return walgotChangeQueuedMsg{Change: change}
+	}
+}
+
+// Callback for sending pending changes to wallabag, one after the other.
+// Changes still failing because wallabag can't be reached stay in the queue,
+// those failing for another reason are dropped (eg: entry deleted since).
+func requestPendingChangesSend(ctx context.Context, queueFile string, changes []walgotPendingChange) tea.Cmd {
+	return func() tea.Msg {
+		msg := walgotChangesSentMsg{QueueFile: queueFile}
+		for _, c := range changes {
+			entry, err := api.GetEntry(ctx, c.ID)
+			if err == nil && isConflictingChange(c, entry) {
+				msg.Conflicts = append(msg.Conflicts, entry)
+				msg.Done = append(msg.Done, c)
+				continue
+			}
+			if err == nil && c.Delete {
+				err = api.DeleteEntry(ctx, c.ID)
+			} else if err == nil {
+				_, err = api.UpdateEntry(ctx, c.ID, c.Archive, c.Starred, c.Public)
+			}
+			if ctx.Err() != nil || api.IsUnreachableError(err) {
+				continue
+			}
+			if err != nil {
+				log.Println("Couldn't send pending change of entry", c.ID, err)
+			} else {
+				msg.Sent++
+			}
+			msg.Done = append(msg.Done, c)
+		}
+
+		return msg
+	}
+}
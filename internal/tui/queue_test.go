@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Strubbl/wallabago/v7"
+)
+
+func TestPendingChangesFile(t *testing.T) {
+	queueFile := filepath.Join(t.TempDir(), "queue.json")
+	queuedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	changes := []walgotPendingChange{
+		{ID: 1, Archive: 1, QueuedAt: queuedAt},
+		{ID: 2, Delete: true, QueuedAt: queuedAt},
+	}
+
+	if loaded, err := loadPendingChanges(queueFile); err != nil || len(loaded) != 0 {
+		t.Errorf("loadPendingChanges(missing): expected no changes, got %v (%v)", loaded, err)
+	}
+	if err := savePendingChanges(queueFile, changes); err != nil {
+		t.Fatalf("savePendingChanges: unexpected error %v", err)
+	}
+	loaded, err := loadPendingChanges(queueFile)
+	if err != nil || fmt.Sprint(loaded) != fmt.Sprint(changes) {
+		t.Errorf("loadPendingChanges: expected %v, got %v (%v)", changes, loaded, err)
+	}
+
+	// The file is removed without changes left:
+	if err := savePendingChanges(queueFile, nil); err != nil {
+		t.Errorf("savePendingChanges(nil): unexpected error %v", err)
+	}
+	if _, err := os.Stat(queueFile); !os.IsNotExist(err) {
+		t.Errorf("savePendingChanges(nil): expected file to be removed, got %v", err)
+	}
+}
+
+func TestQueueChange(t *testing.T) {
+	changes := []walgotPendingChange{{ID: 1, Archive: 1}, {ID: 2, Starred: 1}}
+	var tests = []struct {
+		input       walgotPendingChange
+		expectedIDs []int
+	}{
+		{walgotPendingChange{ID: 3}, []int{1, 2, 3}},
+		{walgotPendingChange{ID: 1}, []int{2, 1}},
+	}
+
+	for _, test := range tests {
+		ids := []int{}
+		for _, c := range queueChange(changes, test.input) {
+			ids = append(ids, c.ID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(test.expectedIDs) {
+			t.Errorf("queueChange(%v): expected %v, got %v", test.input, test.expectedIDs, ids)
+		}
+	}
+}
+
+func TestRemoveChanges(t *testing.T) {
+	before := time.Now()
+	after := before.Add(time.Minute)
+	changes := []walgotPendingChange{{ID: 1, QueuedAt: before}, {ID: 2, QueuedAt: after}}
+	var tests = []struct {
+		inputDone   []walgotPendingChange
+		expectedIDs []int
+	}{
+		{nil, []int{1, 2}},
+		{[]walgotPendingChange{{ID: 1, QueuedAt: before}}, []int{2}},
+		// Queued again since it was sent:
+		{[]walgotPendingChange{{ID: 2, QueuedAt: before}}, []int{1, 2}},
+		{changes, []int{}},
+	}
+
+	for _, test := range tests {
+		ids := []int{}
+		for _, c := range removeChanges(changes, test.inputDone) {
+			ids = append(ids, c.ID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(test.expectedIDs) {
+			t.Errorf("removeChanges(%v): expected %v, got %v", test.inputDone, test.expectedIDs, ids)
+		}
+	}
+}
+
+func TestApplyPendingChanges(t *testing.T) {
+	entries := []wallabago.Item{{ID: 1}, {ID: 2, IsStarred: 1}, {ID: 3}}
+	var tests = []struct {
+		inputChanges []walgotPendingChange
+		expected     string
+	}{
+		{nil, "[1:0/0/false 2:0/1/false 3:0/0/false]"},
+		{[]walgotPendingChange{{ID: 1, Archive: 1, Public: 1}}, "[1:1/0/true 2:0/1/false 3:0/0/false]"},
+		{[]walgotPendingChange{{ID: 2, Starred: 0}, {ID: 3, Delete: true}}, "[1:0/0/false 2:0/0/false]"},
+		{[]walgotPendingChange{{ID: 4, Delete: true}}, "[1:0/0/false 2:0/1/false 3:0/0/false]"},
+	}
+
+	for _, test := range tests {
+		result := []string{}
+		for _, e := range applyPendingChanges(entries, test.inputChanges) {
+			result = append(result, fmt.Sprintf("%d:%d/%d/%v", e.ID, e.IsArchived, e.IsStarred, e.IsPublic))
+		}
+		if fmt.Sprint(result) != test.expected {
+			t.Errorf("applyPendingChanges(%v): expected %v, got %v", test.inputChanges, test.expected, result)
+		}
+	}
+
+	// Entries given are not modified:
+	if entries[1].IsStarred != 1 {
+		t.Errorf("applyPendingChanges: entries given have been modified")
+	}
+}
+
+func TestIsConflictingChange(t *testing.T) {
+	queuedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	var tests = []struct {
+		inputUpdatedAt *wallabago.WallabagTime
+		expected       bool
+	}{
+		{nil, false},
+		{&wallabago.WallabagTime{Time: queuedAt.Add(-time.Hour)}, false},
+		{&wallabago.WallabagTime{Time: queuedAt}, false},
+		{&wallabago.WallabagTime{Time: queuedAt.Add(time.Hour)}, true},
+	}
+
+	for _, test := range tests {
+		change := walgotPendingChange{ID: 1, QueuedAt: queuedAt}
+		if result := isConflictingChange(change, wallabago.Item{ID: 1, UpdatedAt: test.inputUpdatedAt}); result != test.expected {
+			t.Errorf("isConflictingChange(%v): expected %v, got %v", test.inputUpdatedAt, test.expected, result)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// State saved between sessions.
+type walgotState struct {
+	Unread   bool
+	Starred  bool
+	Archived bool
+	Public   bool
+}
+
+// Load state from file.
+func loadState(stateFile string) (walgotState, error) {
+	var state walgotState
+
+	raw, err := os.ReadFile(stateFile)
+	if err != nil {
+		return state, err
+	}
+	err = json.Unmarshal(raw, &state)
+
+	return state, err
+}
+
+// Save state in file.
+func saveState(stateFile string, state walgotState) error {
+	raw, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(stateFile), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(stateFile, raw, 0600)
+}
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Colors, role name -> color.
+// Colors are ANSI 256 colors (eg: "205") or hex colors (eg: "#874BFD").
+type walgotTheme map[string]string
+
+// Default theme, for dark terminals.
+// An empty color uses the terminal default one.
+var defaultTheme = walgotTheme{
+	"spinner":             "205",
+	"accent":              "205",
+	"title":               "",
+	"headerBorder":        "240",
+	"selectedForeground":  "229",
+	"selectedBackground":  "57",
+	"dialogBorder":        "#874BFD",
+	"highlightForeground": "0",
+	"highlightBackground": "220",
+}
+
+// Default theme for light terminals.
+var defaultLightTheme = walgotTheme{
+	"spinner":             "162",
+	"accent":              "162",
+	"title":               "",
+	"headerBorder":        "250",
+	"selectedForeground":  "0",
+	"selectedBackground":  "153",
+	"dialogBorder":        "#5A3FC0",
+	"highlightForeground": "0",
+	"highlightBackground": "228",
+}
+
+// Spinner styles, name -> spinner preset.
+var spinnerStyles = map[string]spinner.Spinner{
+	"dot":       spinner.Dot,
+	"line":      spinner.Line,
+	"minidot":   spinner.MiniDot,
+	"jump":      spinner.Jump,
+	"pulse":     spinner.Pulse,
+	"points":    spinner.Points,
+	"globe":     spinner.Globe,
+	"moon":      spinner.Moon,
+	"monkey":    spinner.Monkey,
+	"meter":     spinner.Meter,
+	"hamburger": spinner.Hamburger,
+}
+
+var hexColorRegexp = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Return the default theme for the given appearance ("auto", "light" or "dark").
+// "auto" detects the terminal background color.
+func getDefaultTheme(appearance string) walgotTheme {
+	switch appearance {
+	case "light":
+		return defaultLightTheme
+	case "dark":
+		return defaultTheme
+	}
+	if lipgloss.HasDarkBackground() {
+		return defaultTheme
+	}
+	return defaultLightTheme
+}
+
+// Return the spinner preset of the given style, dot if empty.
+// An unknown style returns dot with a warning.
+func resolveSpinnerStyle(style string) (spinner.Spinner, string) {
+	if len(style) == 0 {
+		return spinner.Dot, ""
+	}
+	if s, ok := spinnerStyles[style]; ok {
+		return s, ""
+	}
+
+	return spinner.Dot, "unknown spinner style: " + style
+}
+
+// Merge theme colors from configuration with the given default ones.
+// Unknown roles and invalid colors are ignored, a warning is returned for each of them.
+func resolveTheme(custom map[string]string, defaults walgotTheme) (walgotTheme, []string) {
+	theme := walgotTheme{}
+	for role, color := range defaults {
+		theme[role] = color
+	}
+
+	var warnings []string
+	for role, color := range custom {
+		if _, ok := defaults[role]; !ok {
+			warnings = append(warnings, "unknown theme color: "+role)
+			continue
+		}
+		if !isValidColor(color) {
+			warnings = append(warnings, "invalid color for theme "+role+": "+color)
+			continue
+		}
+		theme[role] = color
+	}
+	// Map iteration order is random, keep warnings stable:
+	sort.Strings(warnings)
+
+	return theme, warnings
+}
+
+// Check if a color is an ANSI 256 color or a hex color.
+func isValidColor(color string) bool {
+	if hexColorRegexp.MatchString(color) {
+		return true
+	}
+	n, err := strconv.Atoi(color)
+
+	return err == nil && n >= 0 && n <= 255
+}
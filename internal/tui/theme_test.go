@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/spinner"
+)
+
+func TestIsValidColor(t *testing.T) {
+	var tests = []struct {
+		inputColor string
+		expected   bool
+	}{
+		{"205", true},
+		{"0", true},
+		{"255", true},
+		{"256", false},
+		{"-1", false},
+		{"#874BFD", true},
+		{"#fff", true},
+		{"#ffff", false},
+		{"#GGGGGG", false},
+		{"red", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if result := isValidColor(test.inputColor); result != test.expected {
+			t.Errorf("isValidColor(%v): expected %v, got %v", test.inputColor, test.expected, result)
+		}
+	}
+}
+
+func TestResolveTheme(t *testing.T) {
+	var tests = []struct {
+		inputCustom      map[string]string
+		role             string
+		expectedColor    string
+		expectedWarnings int
+	}{
+		{nil, "spinner", "205", 0},
+		{map[string]string{"spinner": "#00FF00"}, "spinner", "#00FF00", 0},
+		{map[string]string{"spinner": "42"}, "accent", "205", 0},
+		{map[string]string{"spinner": "pink"}, "spinner", "205", 1},
+		{map[string]string{"unknown": "42"}, "spinner", "205", 1},
+		{map[string]string{"title": "42", "foo": "1", "accent": "bar"}, "title", "42", 2},
+	}
+
+	for _, test := range tests {
+		theme, warnings := resolveTheme(test.inputCustom, defaultTheme)
+		if theme[test.role] != test.expectedColor {
+			t.Errorf("resolveTheme(%v): expectedColor for %v %v, got %v", test.inputCustom, test.role, test.expectedColor, theme[test.role])
+		}
+		if len(warnings) != test.expectedWarnings {
+			t.Errorf("resolveTheme(%v): expectedWarnings %v, got %v", test.inputCustom, test.expectedWarnings, warnings)
+		}
+	}
+
+	// Defaults must not be modified by custom theme:
+	if defaultTheme["spinner"] != "205" {
+		t.Errorf("resolveTheme: default theme has been modified")
+	}
+}
+
+func TestGetDefaultTheme(t *testing.T) {
+	if theme := getDefaultTheme("light"); theme["selectedBackground"] != defaultLightTheme["selectedBackground"] {
+		t.Errorf("getDefaultTheme(light): expected light theme, got %v", theme)
+	}
+	if theme := getDefaultTheme("dark"); theme["selectedBackground"] != defaultTheme["selectedBackground"] {
+		t.Errorf("getDefaultTheme(dark): expected dark theme, got %v", theme)
+	}
+
+	// Both themes must define the same roles:
+	for role := range defaultTheme {
+		if _, ok := defaultLightTheme[role]; !ok {
+			t.Errorf("defaultLightTheme: missing color for %v", role)
+		}
+	}
+	if len(defaultLightTheme) != len(defaultTheme) {
+		t.Errorf("defaultLightTheme: expected %v colors, got %v", len(defaultTheme), len(defaultLightTheme))
+	}
+}
+
+func TestResolveSpinnerStyle(t *testing.T) {
+	var tests = []struct {
+		inputStyle      string
+		expected        spinner.Spinner
+		expectedWarning bool
+	}{
+		{"", spinner.Dot, false},
+		{"line", spinner.Line, false},
+		{"moon", spinner.Moon, false},
+		{"unknown", spinner.Dot, true},
+	}
+
+	for _, test := range tests {
+		result, warning := resolveSpinnerStyle(test.inputStyle)
+		if fmt.Sprint(result.Frames) != fmt.Sprint(test.expected.Frames) {
+			t.Errorf("resolveSpinnerStyle(%v): expected %v, got %v", test.inputStyle, test.expected.Frames, result.Frames)
+		}
+		if (len(warning) > 0) != test.expectedWarning {
+			t.Errorf("resolveSpinnerStyle(%v): expectedWarning %v, got %v", test.inputStyle, test.expectedWarning, warning)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Lines repeated at least this number of times in an article are boilerplate
+// (eg: navigation, share buttons), if they are short enough.
+const trimRepeatedLines = 2
+const trimRepeatedMaxLength = 60
+
+// Rules removing boilerplate from articles content (TrimContent and
+// TrimPatterns options), nil when trimming is disabled.
+type walgotTrimRules struct {
+	// Remove short lines repeated in the article:
+	RemoveRepeated bool
+	// Lines matching one of them are removed:
+	Patterns []*regexp.Regexp
+}
+
+// Return the trim rules from configuration, nil if trimming is disabled,
+// and warnings for invalid patterns (ignored).
+func resolveTrimRules(enabled bool, patterns []string) (*walgotTrimRules, []string) {
+	var warnings []string
+	rules := &walgotTrimRules{RemoveRepeated: enabled}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			warnings = append(warnings, "invalid pattern: "+p)
+			continue
+		}
+		rules.Patterns = append(rules.Patterns, re)
+	}
+	if !rules.RemoveRepeated && len(rules.Patterns) == 0 {
+		return nil, warnings
+	}
+
+	return rules, warnings
+}
+
+// Remove boilerplate lines from the content converted as text or markdown,
+// before it is wrapped. Successive blank lines are collapsed into one.
+func trimContent(content string, rules *walgotTrimRules) string {
+	if rules == nil {
+		return content
+	}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	counts := map[string]int{}
+	if rules.RemoveRepeated {
+		for _, line := range lines {
+			counts[strings.TrimSpace(line)]++
+		}
+	}
+
+	var trimmed []string
+	blank := true
+	for _, line := range lines {
+		l := strings.TrimSpace(line)
+		if isTrimmedLine(l, counts[l], rules) {
+			continue
+		}
+		// Blank lines are kept between paragraphs only:
+		if l == "" {
+			if !blank {
+				trimmed = append(trimmed, "")
+			}
+			blank = true
+			continue
+		}
+		trimmed = append(trimmed, line)
+		blank = false
+	}
+	if len(trimmed) > 0 && trimmed[len(trimmed)-1] == "" {
+		trimmed = trimmed[:len(trimmed)-1]
+	}
+
+	return strings.Join(trimmed, "\n")
+}
+
+// Check if a line (trimmed) is boilerplate, count is its number of occurrences.
+// Lines without letters (eg: separators, code fences) are never repeated boilerplate.
+func isTrimmedLine(line string, count int, rules *walgotTrimRules) bool {
+	if line == "" {
+		return false
+	}
+	for _, re := range rules.Patterns {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+
+	return rules.RemoveRepeated &&
+		count >= trimRepeatedLines &&
+		len([]rune(line)) <= trimRepeatedMaxLength &&
+		strings.IndexFunc(line, unicode.IsLetter) >= 0
+}
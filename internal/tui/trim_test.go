@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"testing"
+)
+
+func TestResolveTrimRules(t *testing.T) {
+	var tests = []struct {
+		inputEnabled     bool
+		inputPatterns    []string
+		expectedNil      bool
+		expectedPatterns int
+		expectedWarnings int
+	}{
+		{false, nil, true, 0, 0},
+		{true, nil, false, 0, 0},
+		{false, []string{"^Share"}, false, 1, 0},
+		{false, []string{"("}, true, 0, 1},
+		{true, []string{"^Share", "(", "Subscribe$"}, false, 2, 1},
+	}
+
+	for _, test := range tests {
+		rules, warnings := resolveTrimRules(test.inputEnabled, test.inputPatterns)
+		if (rules == nil) != test.expectedNil {
+			t.Errorf("resolveTrimRules(%v, %v): expectedNil %v, got %v", test.inputEnabled, test.inputPatterns, test.expectedNil, rules)
+			continue
+		}
+		if rules != nil && len(rules.Patterns) != test.expectedPatterns {
+			t.Errorf("resolveTrimRules(%v, %v): expectedPatterns %v, got %v", test.inputEnabled, test.inputPatterns, test.expectedPatterns, len(rules.Patterns))
+		}
+		if len(warnings) != test.expectedWarnings {
+			t.Errorf("resolveTrimRules(%v, %v): expectedWarnings %v, got %v", test.inputEnabled, test.inputPatterns, test.expectedWarnings, warnings)
+		}
+	}
+}
+
+func TestTrimContent(t *testing.T) {
+	noisy := "Home\r\nNews\r\nAbout\r\n\r\n\r\n" +
+		"Share on Twitter\r\n\r\n" +
+		"A real article title\r\n\r\n" +
+		"First paragraph of the article.\r\n\r\n\r\n\r\n" +
+		"* * *\r\n\r\n" +
+		"Second paragraph, with an ad below.\r\n\r\n" +
+		"Advertisement\r\n\r\n" +
+		"* * *\r\n\r\n" +
+		"Home\r\nNews\r\nAbout\r\n\r\n" +
+		"Share on Twitter\r\n\r\n"
+
+	var tests = []struct {
+		inputEnabled  bool
+		inputPatterns []string
+		expected      string
+	}{
+		{false, nil, noisy},
+		{true, nil, "A real article title\n\n" +
+			"First paragraph of the article.\n\n" +
+			"* * *\n\n" +
+			"Second paragraph, with an ad below.\n\n" +
+			"Advertisement\n\n" +
+			"* * *"},
+		{true, []string{"(?i)^advertisement$"}, "A real article title\n\n" +
+			"First paragraph of the article.\n\n" +
+			"* * *\n\n" +
+			"Second paragraph, with an ad below.\n\n" +
+			"* * *"},
+		{false, []string{"^Share on"}, "Home\nNews\nAbout\n\n" +
+			"A real article title\n\n" +
+			"First paragraph of the article.\n\n" +
+			"* * *\n\n" +
+			"Second paragraph, with an ad below.\n\n" +
+			"Advertisement\n\n" +
+			"* * *\n\n" +
+			"Home\nNews\nAbout"},
+	}
+
+	for _, test := range tests {
+		rules, _ := resolveTrimRules(test.inputEnabled, test.inputPatterns)
+		if result := trimContent(noisy, rules); result != test.expected {
+			t.Errorf("trimContent(%v, %v): expected %q, got %q", test.inputEnabled, test.inputPatterns, test.expected, result)
+		}
+	}
+}
@@ -1,20 +1,22 @@
 package tui
 
 import (
-	"bytes"
-	"encoding/gob"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
-	"os"
+	"math/rand"
+	"strings"
 	"time"
 
 	"git.bacardi55.io/bacardi55/walgot/internal/api"
 	"git.bacardi55.io/bacardi55/walgot/internal/config"
+	"git.bacardi55.io/bacardi55/walgot/internal/notify"
 
 	"github.com/Strubbl/wallabago/v7"
 
+	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -23,6 +25,36 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Delay before applying a new window size.
+const resizeDelay = 100 * time.Millisecond
+
+// Delay before clearing the update message in the footer.
+const updateMessageDelay = 5 * time.Second
+
+// Maximum delay between the two key presses of "gg".
+const doubleKeyDelay = 500 * time.Millisecond
+
+// Terminal width from which the preview pane is displayed next to the list,
+// unless the list width is configured.
+const previewMinTermWidth = 160
+
+// Minimal width of the preview pane.
+const previewMinWidth = 40
+
+// Minimal terminal size, a message is displayed instead of views below it.
+const minTermWidth = 40
+const minTermHeight = 12
+
+// Number of lines scrolled by a mouse wheel step, in the reading view.
+const mouseWheelLines = 3
+
+// Maximal number of characters of the excerpt column.
+const excerptLength = 60
+
+// Label of the default profile (CredentialsFile option), in the profiles view.
+// Profile names can't contain parentheses.
+const defaultProfileLabel = "(default)"
+
 // ** Model related Struct ** //
 
 // Terminal physical size:
@@ -33,11 +65,21 @@ type termSize struct {
 
 // TableView filter options
 type walgotTableFilters struct {
-	Archived bool
-	Starred  bool
-	Unread   bool
-	Public   bool
-	Search   string
+	// Read state: "unread", "archived" or "" for all entries:
+	ReadState string
+	Starred   bool
+	Public    bool
+	Search    string
+	Tag       string
+	// Server side search, IDs of matching entries:
+	ServerSearch    string
+	ServerSearchIDs map[int]bool
+	// Date range as entered (eg: "7d"), entries created (or updated,
+	// depending on DateField) from Since and before Until, if not zero:
+	DateRange string
+	DateField string
+	Since     time.Time
+	Until     time.Time
 }
 
 // TableView Sort options
@@ -52,12 +94,55 @@ type walgotTableOptions struct {
 	Sorts   walgotTableSorts
 }
 
+// TableView rows rendering options
+type walgotRowsOptions struct {
+	MaxWidth      int
+	Columns       []string
+	Compact       bool
+	DateFormat    string
+	RelativeDates bool
+	// Entries selected for batch actions:
+	Marked map[int]bool
+	// Excerpts cache, nil when the excerpt column is disabled:
+	Excerpts map[int]string
+}
+
+// Article content conversion options
+type walgotContentOptions struct {
+	WrapWidth int
+	// "markdown" or "text":
+	Renderer string
+	// Links numbered and listed at the end of the content:
+	LinkReferences bool
+	Trim           *walgotTrimRules
+}
+
+// Detail view content options
+type walgotDetailOptions struct {
+	walgotContentOptions
+	// Metadata panel above the article:
+	Metadata   bool
+	DateFormat string
+	Highlight  string
+	Theme      walgotTheme
+}
+
 // Dialog Box:
 type walgotDialog struct {
 	Message   string
 	TextInput textinput.Model
 	ShowInput bool
 	Action    string
+	// Entry the action applies to, if any:
+	EntryID int
+}
+
+// Search in the entry being read.
+// Lines are the viewport lines matching the term, Current the one displayed.
+type walgotArticleSearch struct {
+	Term    string
+	Lines   []int
+	Current int
 }
 
 // Walgot error message:
@@ -70,41 +155,259 @@ type wallabagoResponseErrorMsg struct {
 type model struct {
 	// Sub models related:
 	Table         table.Model
+	TagsTable     table.Model
+	ProfilesTable table.Model
+	// Number of rows of the tags and profiles tables:
+	NbTags        int
+	NbProfiles    int
 	Viewport      viewport.Model
 	Dialog        walgotDialog
 	Spinner       spinner.Model
+	Progress      progress.Model
 	UpdateMessage string
 	// Tui Status related
-	Ready       bool
-	Reloading   bool
-	CurrentView string
-	Options     walgotTableOptions
+	Ready        bool
+	Reloading    bool
+	LoadProgress float64
+	// Number of entries retrieved during reload:
+	LoadedEntries int
+	CurrentView   string
+	// View displayed when not reading, "list" or "grouped" (by domain):
+	BrowsingView string
+	Options      walgotTableOptions
 	// Wallabag(o) related:
 	Entries              []wallabago.Item
 	SelectedID           int
 	TotalEntriesOnServer int
+	NbFilteredEntries    int
+	// Number of entries on server when last checked, 0 if unknown:
+	ServerCount int
+	// Entry being retrieved again from wallabag, 0 if none:
+	RefreshingID int
+	// Reading time of entries matching filters, in minutes:
+	FilteredReadingTime int
+	// Pagination mode, only the current page is loaded:
+	Paginated   bool
+	CurrentPage int
+	TotalPages  int
+	// Entries selected for batch actions, by ID:
+	Marked map[int]bool
+	// Grouped by domain view, expanded domains and cursor position:
+	ExpandedDomains map[string]bool
+	GroupedCursor   int
+	// Scroll position of read entries, by ID:
+	ScrollPositions map[int]int
+	// Search in the entry being read:
+	ArticleSearch walgotArticleSearch
+	// Metadata panel displayed above articles, for the session:
+	ShowMetadata bool
+	// Columns of the list on wide screens:
+	Columns []string
+	// Excerpts of entries by ID, nil when the excerpt column is disabled:
+	Excerpts map[int]string
+	// Converted content of read entries:
+	ContentCache *walgotContentCache
+	// Last log lines and the logs view:
+	Logs         *LogBuffer
+	LogsViewport viewport.Model
+	// Context of API calls, canceled when quitting:
+	Ctx    context.Context
+	Cancel context.CancelFunc
+	// Context of the running reload, canceled with esc:
+	ReloadCtx    context.Context
+	ReloadCancel context.CancelFunc
+	// An automatic refresh is running in background, list actions stay available:
+	AutoRefreshing bool
+	// IDs of the last retrieved entries, to find new ones after a refresh:
+	SeenIDs map[int]bool
+	// Results of the wallabag search that weren't loaded, listed until the search is cleared:
+	ServerSearchEntries []wallabago.Item
 	// Configs
-	NbEntriesPerAPICall int
-	TermSize            termSize
-	DebugMode           bool
+	NbEntriesPerAPICall  int
+	NbConcurrentAPICalls int
+	MaxOpenAtOnce        int
+	CacheFile            string
+	CacheTTL             time.Duration
+	AutoRefreshInterval  time.Duration
+	NotifyNewEntries     bool
+	ConfirmQuit          bool
+	NoCache              bool
+	Offline              bool
+	ReadOnly             bool
+	ShowEmptyTags        bool
+	ShowPreview          bool
+	PageJumpRows         int
+	StateFile            string
+	Keys                 walgotKeys
+	Theme                walgotTheme
+	DateFormat           string
+	RelativeDates        bool
+	ReadingWidth         int
+	ListWidth            int
+	ContentRenderer      string
+	NoLinkReferences     bool
+	TrimRules            *walgotTrimRules
+	ShowStatusLine       bool
+	CompactList          bool
+	ExportPath           string
+	EndpointHost         string
+	EndpointUser         string
+	Profile              string
+	TermSize             termSize
+	ResizeID             int
+	// Credentials file of each profile, "" is the default one:
+	ProfileCredentials map[string]string
+	// Cache file of the default profile, other profiles have their own:
+	BaseCacheFile string
+	// Same for the file of pending changes:
+	BaseQueueFile string
+	QueueFile     string
+	// Changes not sent to wallabag yet, offline or when it couldn't be reached:
+	PendingChanges []walgotPendingChange
+	SendingChanges bool
+	// Keys of the current view displayed over it:
+	ShowKeysPopup bool
+	// Random entries picker, seeded on start:
+	Rand *rand.Rand
+	// First key of a double key press (eg: "gg"), waiting for the second one:
+	PendingKey   string
+	PendingKeyID int
+	DebugMode    bool
 }
 
 // NewModel returns default model for walgot.
-func NewModel(config config.WalgotConfig) model {
+// Logs are the last log lines, displayed in the logs view.
+func NewModel(config config.WalgotConfig, logs *LogBuffer) model {
+	filters := walgotTableFilters{
+		ReadState: getReadState(config.DefaultListViewUnread, false),
+		Starred:   config.DefaultListViewStarred,
+		Public:    config.DefaultListViewPublic,
+	}
+	// Startup filters replace default ones and those of previous session:
+	if startup, err := config.ParseStartupFilter(); err == nil && len(config.StartupFilter) > 0 {
+		filters = walgotTableFilters{
+			ReadState: getReadState(startup.Unread, startup.Archived),
+			Starred:   startup.Starred,
+			Public:    startup.Public,
+			Tag:       startup.Tag,
+			Search:    startup.Search,
+		}
+	} else if len(config.StateFile) > 0 && !config.ResetFilters {
+		// Restore filters from previous session:
+		if state, err := loadState(config.StateFile); err == nil {
+			filters.ReadState = getReadState(state.Unread, state.Archived)
+			filters.Starred = state.Starred
+			filters.Public = state.Public
+		} else if config.DebugMode {
+			log.Println("Couldn't load state file", err)
+		}
+	}
+
+	// Startup view, leaving the tags view returns to the list:
+	browsingView := "list"
+	if config.StartupView == "grouped" {
+		browsingView = "grouped"
+	}
+	currentView := browsingView
+	if config.StartupView == "tags" {
+		currentView = "tags"
+	}
+
+	// Profiles available in the profile switcher:
+	profileCredentials := map[string]string{"": config.CredentialsFile}
+	for name, credentialsFile := range config.Profiles {
+		profileCredentials[name] = credentialsFile
+	}
+
+	// Keybindings, a bad configuration shouldn't prevent walgot from starting:
+	keys, warnings := resolveKeybindings(config.Keybindings)
+	for _, w := range warnings {
+		log.Println("Warning:", w)
+	}
+	// Same for theme colors:
+	theme, warnings := resolveTheme(config.Theme, getDefaultTheme(config.Appearance))
+	for _, w := range warnings {
+		log.Println("Warning:", w)
+	}
+	// Same for list columns:
+	columns, warnings := resolveTableColumns(config.Columns, config.ShowTagsColumn, config.ShowExcerptColumn)
+	for _, w := range warnings {
+		log.Println("Warning:", w)
+	}
+	// And articles trimming:
+	trimRules, warnings := resolveTrimRules(config.TrimContent, config.TrimPatterns)
+	for _, w := range warnings {
+		log.Println("Warning:", w)
+	}
+
 	s := spinner.New()
-	s.Spinner = spinner.Dot
+	spinnerStyle, warning := resolveSpinnerStyle(config.SpinnerStyle)
+	if len(warning) > 0 {
+		log.Println("Warning:", warning)
+	}
+	s.Spinner = spinnerStyle
 	s.Style = lipgloss.
 		NewStyle().
-		Foreground(lipgloss.Color("205"))
+		Foreground(lipgloss.Color(theme["spinner"]))
+
+	host, user := api.GetEndpoint()
+	ctx, cancel := context.WithCancel(context.Background())
+	// Entries are loaded on start:
+	reloadCtx, reloadCancel := context.WithCancel(ctx)
 
-	return model{
+	m := model{
 		SelectedID:           0,
 		Ready:                false,
 		Reloading:            true,
-		CurrentView:          "list",
+		CurrentView:          currentView,
+		BrowsingView:         browsingView,
 		TotalEntriesOnServer: 0,
+		Paginated:            config.PaginatedMode,
+		CurrentPage:          1,
+		ScrollPositions:      map[int]int{},
+		Marked:               map[int]bool{},
+		ExpandedDomains:      map[string]bool{},
+		ContentCache:         newContentCache(contentCacheSize),
+		Logs:                 logs,
+		Columns:              columns,
+		TrimRules:            trimRules,
+		Rand:                 rand.New(rand.NewSource(time.Now().UnixNano())),
+		Ctx:                  ctx,
+		Cancel:               cancel,
+		ReloadCtx:            reloadCtx,
+		ReloadCancel:         reloadCancel,
 		Spinner:              s,
+		Progress:             progress.New(progress.WithDefaultGradient()),
 		NbEntriesPerAPICall:  config.NbEntriesPerAPICall,
+		NbConcurrentAPICalls: config.NbConcurrentAPICalls,
+		MaxOpenAtOnce:        config.MaxOpenAtOnce,
+		BaseCacheFile:        config.CacheFile,
+		BaseQueueFile:        config.QueueFile,
+		CacheTTL:             config.CacheTTL,
+		AutoRefreshInterval:  config.AutoRefreshInterval,
+		NotifyNewEntries:     config.NotifyNewEntries,
+		ConfirmQuit:          config.ConfirmQuit,
+		NoCache:              config.NoCache,
+		Offline:              config.Offline,
+		ReadOnly:             config.ReadOnly,
+		ShowEmptyTags:        config.ShowEmptyTags,
+		ShowPreview:          !config.NoPreview,
+		PageJumpRows:         config.PageJumpRows,
+		StateFile:            config.StateFile,
+		Keys:                 keys,
+		Theme:                theme,
+		DateFormat:           config.DateFormat,
+		RelativeDates:        config.RelativeDates,
+		ReadingWidth:         config.ReadingWidth,
+		ListWidth:            config.ListWidth,
+		ContentRenderer:      config.ContentRenderer,
+		NoLinkReferences:     config.NoLinkReferences,
+		ShowStatusLine:       config.ShowStatusLine,
+		CompactList:          config.ListDensity == "compact",
+		ExportPath:           config.ExportPath,
+		EndpointHost:         host,
+		EndpointUser:         user,
+		ProfileCredentials:   profileCredentials,
 		DebugMode:            config.DebugMode,
 		Dialog: walgotDialog{
 			Message:   "",
@@ -113,25 +416,75 @@ func NewModel(config config.WalgotConfig) model {
 			Action:    "",
 		},
 		Options: walgotTableOptions{
-			Filters: walgotTableFilters{
-				Unread:  config.DefaultListViewUnread,
-				Starred: config.DefaultListViewStarred,
-				Public:  config.DefaultListViewPublic,
-			},
+			Filters: filters,
 			Sorts: walgotTableSorts{
-				Field: "created",
-				Order: "desc",
+				Field: config.DefaultSortField,
+				Order: config.DefaultSortOrder,
 			},
 		},
 	}
+	setProfile(&m, config.Profile)
+	// Excerpts are computed only if displayed:
+	if hasTableColumn(columns, "Excerpt") {
+		m.Excerpts = map[int]string{}
+	}
+
+	return m
 }
 
 // Response message for number of entities from Wallabago
 type wallabagoResponseNbEntitiesMsg int
 
+// Response message for number of entities on server, without reloading them.
+type wallabagoResponseServerCountMsg int
+
 // Response message for all entities from Wallabago.
 type wallabagoResponseEntitiesMsg []wallabago.Item
 
+// Response message for entries from Wallabago, when some pages couldn't be retrieved.
+type wallabagoResponsePartialEntitiesMsg struct {
+	Entries     []wallabago.Item
+	FailedPages int
+	TotalPages  int
+}
+
+// Response message for a page of entries, in pagination mode.
+type wallabagoResponsePageMsg struct {
+	Page    int
+	Pages   int
+	Total   int
+	Entries []wallabago.Item
+}
+
+// Progress message while retrieving entries via API.
+// Done and Total are numbers of API calls, Loaded the number of entries retrieved.
+type wallabagoLoadProgressMsg struct {
+	Done     int
+	Total    int
+	Loaded   int
+	messages chan tea.Msg
+}
+
+// Progress message while exporting entries.
+type walgotExportProgressMsg struct {
+	Done     int
+	Total    int
+	messages chan tea.Msg
+}
+
+// Message sent once entries have been exported.
+type walgotExportDoneMsg struct {
+	Exported  int
+	Failed    int
+	Directory string
+}
+
+// Debounced window resize, with the resize ID.
+type walgotResizeMsg int
+
+// Time to refresh entries automatically.
+type walgotAutoRefreshMsg bool
+
 // Response message for entity update.
 type wallabagoResponseEntityUpdateMsg struct {
 	UpdatedEntry wallabago.Item
@@ -145,6 +498,12 @@ type wallabagoResponseAddEntryMsg struct {
 	Entry wallabago.Item
 }
 
+// Search entries via API message.
+type wallabagoResponseSearchMsg struct {
+	Term    string
+	Entries []wallabago.Item
+}
+
 // Delete entry message.
 type wallabagoResponseDeleteEntryMsg int
 
@@ -154,105 +513,372 @@ type walgotSelectRowMsg int
 // Search for an entry message.
 type walgotSearchEntryMsg string
 
+// Double key press delay expired message, with the ID of the pending key.
+type walgotPendingKeyTimeoutMsg int
+
+// URL opened in browser message.
+type walgotURLOpenedMsg string
+
+// Entry retrieved again from API.
+type wallabagoResponseEntryRefreshMsg struct {
+	Entry wallabago.Item
+}
+
+// Annotations of an entry retrieved from API.
+type wallabagoResponseAnnotationsMsg struct {
+	ID          int
+	Annotations []wallabago.Annotation
+}
+
+// URLs opened in browser message, with URLs that couldn't be opened.
+type walgotURLsOpenedMsg struct {
+	Opened int
+	Failed []string
+}
+
+// URL copied to clipboard message.
+type walgotURLCopiedMsg string
+
+// Filter entries by tag message.
+type walgotFilterTagMsg string
+
+// ConfigWarnings returns problems found in keybindings, theme and columns configuration.
+func ConfigWarnings(config config.WalgotConfig) []string {
+	var warnings []string
+	_, keysWarnings := resolveKeybindings(config.Keybindings)
+	for _, w := range keysWarnings {
+		warnings = append(warnings, "Keybindings: "+w)
+	}
+	_, themeWarnings := resolveTheme(config.Theme, defaultTheme)
+	for _, w := range themeWarnings {
+		warnings = append(warnings, "Theme: "+w)
+	}
+	_, columnsWarnings := resolveTableColumns(config.Columns, config.ShowTagsColumn, config.ShowExcerptColumn)
+	for _, w := range columnsWarnings {
+		warnings = append(warnings, "Columns: "+w)
+	}
+	_, trimWarnings := resolveTrimRules(config.TrimContent, config.TrimPatterns)
+	for _, w := range trimWarnings {
+		warnings = append(warnings, "TrimPatterns: "+w)
+	}
+
+	return warnings
+}
+
+// Callback for loading entries from cache only, whatever their age.
+func requestCachedEntries(cacheFile string) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := loadEntriesFromCache(cacheFile, 0)
+		if err != nil {
+			return wallabagoResponseErrorMsg{
+				message:        "Error:\n couldn't load the entries from cache",
+				wallabagoError: err,
+			}
+		}
+		if len(entries) == 0 {
+			return wallabagoResponseErrorMsg{
+				message:        "Error:\n no cached entries for offline mode.\n Start walgot online first to cache them.",
+				wallabagoError: errors.New("no cached entries in " + cacheFile),
+			}
+		}
+
+		return wallabagoResponseEntitiesMsg(entries)
+	}
+}
+
 // Callback for requesting the total number of entries via API.
-func requestWallabagNbEntries() tea.Msg {
-	// Get total number of articles:
-	nbArticles, e := api.GetNbTotalEntries()
+func requestWallabagNbEntries(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		// Get total number of articles:
+		nbArticles, e := api.GetNbTotalEntries(ctx)
 
-	if e != nil {
-		return wallabagoResponseErrorMsg{
-			message:        "Error:\n couldn't retrieve the total number of entries from wallabag API",
-			wallabagoError: e,
+		if e != nil {
+			return wallabagoResponseErrorMsg{
+				message:        "Error:\n couldn't retrieve the total number of entries from wallabag API",
+				wallabagoError: e,
+			}
 		}
+
+		return wallabagoResponseNbEntitiesMsg(nbArticles)
 	}
+}
 
-	return wallabagoResponseNbEntitiesMsg(nbArticles)
+// Callback for requesting the number of entries on wallabag, to compare it
+// with loaded entries.
+func requestWallabagServerCount(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		nbArticles, e := api.GetNbTotalEntries(ctx)
+		if e != nil {
+			return wallabagoResponseErrorMsg{
+				message:        "Error:\n couldn't retrieve the number of entries from wallabag API",
+				wallabagoError: e,
+			}
+		}
+
+		return wallabagoResponseServerCountMsg(nbArticles)
+	}
+}
+
+// Callback for requesting a page of entries via API, in pagination mode.
+// Filters are applied by wallabag, sort too if possible (created or updated).
+func requestWallabagEntriesPage(ctx context.Context, page, nbEntriesPerAPICall int, filters walgotTableFilters, sorts walgotTableSorts) tea.Cmd {
+	archive, starred, public := -1, -1, -1
+	if filters.ReadState == "unread" {
+		archive = 0
+	} else if filters.ReadState == "archived" {
+		archive = 1
+	}
+	if filters.Starred {
+		starred = 1
+	}
+	if filters.Public {
+		public = 1
+	}
+	sortField := "created"
+	if sorts.Field == "updated" {
+		sortField = "updated"
+	}
+
+	return func() tea.Msg {
+		r, err := api.GetEntriesPage(ctx, nbEntriesPerAPICall, page, archive, starred, public, filters.Tag, sortField, sorts.Order)
+		if err != nil {
+			return wallabagoResponseErrorMsg{
+				message:        "Error:\n couldn't retrieve the entries from wallabag API",
+				wallabagoError: err,
+			}
+		}
+
+		return wallabagoResponsePageMsg{
+			Page:    r.Page,
+			Pages:   r.Pages,
+			Total:   r.Total,
+			Entries: r.Embedded.Items,
+		}
+	}
 }
 
 // Callback for requesting entries via API.
-func requestWallabagEntries(nbArticles, nbEntriesPerAPICall int, sortField, sortOrder string) tea.Cmd {
-	cacheFilename := "/tmp/walgot-cache.dat"
+// Entries are retrieved in a goroutine, sending a progress message after
+// each API call and the retrieved entries (or an error) at the end.
+func requestWallabagEntries(ctx context.Context, nbArticles, nbEntriesPerAPICall, nbConcurrentAPICalls int, sortField, sortOrder, cacheFile string, cacheTTL time.Duration, noCache bool) tea.Cmd {
 	entries := []wallabago.Item{}
-	// Load cache if present
-	info, err := os.Stat(cacheFilename)
-	if err == nil {
-		if info.Mode()&1<<2 != 0 {
-			// https://stackoverflow.com/questions/45429210/how-do-i-check-a-files-permissions-in-linux-using-go
-			// other users have read permission
+	// Load cache if present and not expired:
+	if !noCache {
+		var err error
+		entries, err = loadEntriesFromCache(cacheFile, cacheTTL)
+		if err != nil {
 			return func() tea.Msg {
 				return wallabagoResponseErrorMsg{
-					message:        "Error:\n couldn't read cache file for security reasons",
+					message:        "Error:\n couldn't load the entries from cache",
 					wallabagoError: err,
 				}
 			}
 		}
+	}
 
-		if content, err := os.ReadFile(cacheFilename); err == nil {
-			err = gob.NewDecoder(bytes.NewBuffer(content)).Decode(&entries)
-			if err != nil {
-				return func() tea.Msg {
-					return wallabagoResponseErrorMsg{
-						message:        "Error:\n couldn't load the entries from cache",
-						wallabagoError: err,
-					}
+	return func() tea.Msg {
+		if len(entries) > 0 {
+			return wallabagoResponseEntitiesMsg(entries)
+		}
+
+		messages := make(chan tea.Msg)
+		go fetchWallabagEntries(ctx, messages, nbArticles, nbEntriesPerAPICall, nbConcurrentAPICalls, sortField, sortOrder, cacheFile)
+
+		return <-messages
+	}
+}
+
+// Retrieve all entries via API, page by page.
+// Up to nbConcurrentAPICalls pages are retrieved at the same time.
+// Messages are sent to the given channel, which is closed at the end.
+// Remaining calls are aborted when ctx is canceled.
+func fetchWallabagEntries(ctx context.Context, messages chan tea.Msg, nbArticles, nbEntriesPerAPICall, nbConcurrentAPICalls int, sortField, sortOrder, cacheFile string) {
+	defer close(messages)
+
+	limitArticleByAPICall := nbEntriesPerAPICall
+	nbCalls := getRequiredNbAPICalls(nbArticles, limitArticleByAPICall)
+	if nbConcurrentAPICalls <= 0 {
+		nbConcurrentAPICalls = 1
+	}
+
+	type pageResult struct {
+		page  int
+		items []wallabago.Item
+		err   error
+	}
+	// Buffered so that workers never block, even after an error:
+	pages := make(chan int, nbCalls)
+	results := make(chan pageResult, nbCalls)
+	stop := make(chan struct{})
+	defer close(stop)
+
+	for i := 1; i < nbCalls+1; i++ {
+		pages <- i
+	}
+	close(pages)
+
+	for w := 0; w < nbConcurrentAPICalls; w++ {
+		go func() {
+			for page := range pages {
+				// Don't start new calls if fetch has been stopped or canceled:
+				select {
+				case <-stop:
+					return
+				case <-ctx.Done():
+					results <- pageResult{page, nil, ctx.Err()}
+					continue
+				default:
 				}
+				r, err := api.GetEntries(ctx, limitArticleByAPICall, page, sortField, sortOrder)
+				results <- pageResult{page, r.Embedded.Items, err}
 			}
+		}()
+	}
+
+	// Results are stored by page to keep the entries order:
+	entriesByPage := make([][]wallabago.Item, nbCalls)
+	// Failing pages don't stop the others, the list will be incomplete:
+	failedPages := 0
+	loaded := 0
+	var lastErr error
+	for done := 1; done < nbCalls+1; done++ {
+		r := <-results
+		if r.err != nil {
+			failedPages++
+			lastErr = r.err
+			log.Println("Couldn't retrieve entries page", r.page, r.err)
+		} else {
+			entriesByPage[r.page-1] = r.items
+			loaded += len(r.items)
+		}
+		messages <- wallabagoLoadProgressMsg{
+			Done:     done,
+			Total:    nbCalls,
+			Loaded:   loaded,
+			messages: messages,
 		}
 	}
 
-	return func() tea.Msg {
-		if len(entries) > 0 {
-			return wallabagoResponseEntitiesMsg(entries)
+	// Canceled fetch, entries retrieved so far are dropped:
+	if ctx.Err() != nil {
+		messages <- wallabagoResponseErrorMsg{
+			message:        "Error:\n entries loading has been canceled",
+			wallabagoError: ctx.Err(),
+		}
+		return
+	}
+	if failedPages == nbCalls && nbCalls > 0 {
+		messages <- wallabagoResponseErrorMsg{
+			message:        "Error:\n couldn't retrieve the entries from wallabag API",
+			wallabagoError: lastErr,
+		}
+		return
+	}
+
+	entries := []wallabago.Item{}
+	for _, items := range entriesByPage {
+		entries = append(entries, items...)
+	}
+	// Incomplete list isn't cached:
+	if failedPages > 0 {
+		messages <- wallabagoResponsePartialEntitiesMsg{
+			Entries:     entries,
+			FailedPages: failedPages,
+			TotalPages:  nbCalls,
+		}
+		return
+	}
+	// TODO: sortField and sortOrder can be provided and may be used for
+	// more specific queries, which would then possibly circumvent the cache.
+	if err := saveEntriesToCache(cacheFile, entries); err != nil {
+		msg := "Error:\n couldn't cache Wallabag entries: %s: %s"
+		messages <- wallabagoResponseErrorMsg{
+			message:        fmt.Sprintf(msg, cacheFile, err),
+			wallabagoError: err,
 		}
-		limitArticleByAPICall := nbEntriesPerAPICall
-		nbCalls := getRequiredNbAPICalls(nbArticles, limitArticleByAPICall)
+		return
+	}
+
+	messages <- wallabagoResponseEntitiesMsg(entries)
+}
+
+// Wait for the next message while entries are retrieved.
+func waitForWallabagEntries(messages chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-messages
+	}
+}
+
+// Clear the update message after it has been displayed enough time.
+func clearUpdateMessageCmd() tea.Cmd {
+	return tea.Tick(updateMessageDelay, func(t time.Time) tea.Msg {
+		return wallabagoResponseClearMsg(true)
+	})
+}
+
+// Apply window size after a short delay, if no other resize happened.
+func resizeCommand(id int) tea.Cmd {
+	return tea.Tick(resizeDelay, func(t time.Time) tea.Msg {
+		return walgotResizeMsg(id)
+	})
+}
+
+// Refresh entries after the given interval, disabled if 0.
+func autoRefreshCommand(interval time.Duration) tea.Cmd {
+	if interval <= 0 {
+		return nil
+	}
+
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
+		return walgotAutoRefreshMsg(true)
+	})
+}
 
-		// TODO: Move this to async channel?
-		// Might not be a good idea with the ELM architecture?
-		// var entries []wallabago.Item
-		for i := 1; i < nbCalls+1; i++ {
-			r, err := api.GetEntries(limitArticleByAPICall, i, sortField, sortOrder)
+// Export entries as markdown files, in background.
+func requestEntriesExport(entries []wallabago.Item, directory, fileTemplate string) tea.Cmd {
+	return func() tea.Msg {
+		messages := make(chan tea.Msg)
+		go exportEntries(messages, entries, directory, fileTemplate)
 
+		return <-messages
+	}
+}
+
+// Callback for searching entries via API.
+func requestWallabagSearch(ctx context.Context, term string, nbEntriesPerAPICall int) tea.Cmd {
+	return func() tea.Msg {
+		entries := []wallabago.Item{}
+		for page, nbPages := 1, 1; page <= nbPages; page++ {
+			r, err := api.SearchEntries(ctx, term, page, nbEntriesPerAPICall)
 			if err != nil {
 				return wallabagoResponseErrorMsg{
-					message:        "Error:\n couldn't retrieve the entries from wallabag API",
+					message:        "Error:\n couldn't search entries on wallabag API",
 					wallabagoError: err,
 				}
 			}
-
+			nbPages = r.Pages
 			entries = append(entries, r.Embedded.Items...)
 		}
-		// TODO cache here, use args for deciding when to cache and when to
-		// return from cache.  Might not be the best position anyways, since
-		// sortField and sortOrder can be provided and may be used for more
-		// specific queries, which would then possibly circumvent the cache,
-		// provided the cache would work for queries without filters set.
-		file, err := os.OpenFile(cacheFilename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
-		defer file.Close()
-		if err != nil {
-			msg := "Error:\n couldn't open file to cache Wallabag entries: %s: %s"
-			return wallabagoResponseErrorMsg{
-				message:        fmt.Sprintf(msg, cacheFilename, err),
-				wallabagoError: err,
-			}
-		}
-		err = gob.NewEncoder(file).Encode(entries)
-		if err != nil {
-			return wallabagoResponseErrorMsg{
-				message:        fmt.Sprintf("Error:\n couldn't decode cache file to Wallabag entries slice: %s", err),
-				wallabagoError: err,
-			}
-		}
 
-		return wallabagoResponseEntitiesMsg(entries)
+		return wallabagoResponseSearchMsg{
+			Term:    term,
+			Entries: entries,
+		}
 	}
 }
 
 // Callback for updating an entry status via API.
-func requestWallabagEntryUpdate(entryID, archive, starred, public int) tea.Cmd {
+func requestWallabagEntryUpdate(ctx context.Context, entryID, archive, starred, public int) tea.Cmd {
 	return func() tea.Msg {
 		// Send PATCH via API:
-		r, err := api.UpdateEntry(entryID, archive, starred, public)
+		r, err := api.UpdateEntry(ctx, entryID, archive, starred, public)
+		// Sent later if wallabag can't be reached:
+		if api.IsUnreachableError(err) {
+			return walgotChangeQueuedMsg{
+				Change: walgotPendingChange{ID: entryID, Archive: archive, Starred: starred, Public: public},
+				Err:    err,
+			}
+		}
 		if err != nil {
 			return wallabagoResponseErrorMsg{
 				message:        "Error:\n Couldn't update the selected entry",
@@ -273,8 +899,23 @@ func requestWallabagEntryUpdate(entryID, archive, starred, public int) tea.Cmd {
 	}
 }
 
+// Callback for replacing the tags of an entry via API.
+func requestWallabagSetTags(ctx context.Context, entry wallabago.Item, labels []string) tea.Cmd {
+	return func() tea.Msg {
+		item, err := api.SetTags(ctx, entry, labels)
+		if err != nil {
+			return wallabagoResponseErrorMsg{
+				message:        "Error:\n Couldn't update the tags of the entry",
+				wallabagoError: err,
+			}
+		}
+
+		return wallabagoResponseEntityUpdateMsg{item}
+	}
+}
+
 // Callback for adding an entry via API.
-func requestWallabagAddEntry(url string) tea.Cmd {
+func requestWallabagAddEntry(ctx context.Context, url string) tea.Cmd {
 	return func() tea.Msg {
 		if !isValidURL(url) {
 			return wallabagoResponseErrorMsg{
@@ -282,7 +923,7 @@ func requestWallabagAddEntry(url string) tea.Cmd {
 				wallabagoError: errors.New("invalid URL given"),
 			}
 		}
-		r, err := api.AddEntry(url)
+		r, err := api.AddEntry(ctx, url)
 		if err != nil {
 			return wallabagoResponseErrorMsg{
 				message:        "Error:\n Couldn't add the entry",
@@ -297,12 +938,16 @@ func requestWallabagAddEntry(url string) tea.Cmd {
 }
 
 // Callback for deleting an entry via API.
-func requestWallabagEntryDelete(id int) tea.Cmd {
+func requestWallabagEntryDelete(ctx context.Context, id int) tea.Cmd {
 	return func() tea.Msg {
-		err := api.DeleteEntry(id)
+		err := api.DeleteEntry(ctx, id)
+		// Sent later if wallabag can't be reached:
+		if api.IsUnreachableError(err) {
+			return walgotChangeQueuedMsg{Change: walgotPendingChange{ID: id, Delete: true}, Err: err}
+		}
 		if err != nil {
 			return wallabagoResponseErrorMsg{
-				message:        "Error:\n Couldn't add the entry",
+				message:        "Error:\n Couldn't delete the entry",
 				wallabagoError: err,
 			}
 		}
@@ -311,6 +956,98 @@ func requestWallabagEntryDelete(id int) tea.Cmd {
 	}
 }
 
+// Callback for retrieving an entry again via API.
+func requestWallabagEntryRefresh(ctx context.Context, id int) tea.Cmd {
+	return func() tea.Msg {
+		item, err := api.GetEntry(ctx, id)
+		if err != nil {
+			return wallabagoResponseErrorMsg{
+				message:        "Error:\n Couldn't refresh the entry",
+				wallabagoError: err,
+			}
+		}
+
+		return wallabagoResponseEntryRefreshMsg{item}
+	}
+}
+
+// Callback for retrieving annotations of an entry via API.
+// Errors are only logged, annotations shouldn't prevent reading.
+func requestWallabagAnnotations(ctx context.Context, id int, debugMode bool) tea.Cmd {
+	return func() tea.Msg {
+		annotations, err := api.GetAnnotations(ctx, id)
+		if err != nil {
+			if debugMode {
+				log.Println("Couldn't retrieve annotations of entry", id, err)
+			}
+			return nil
+		}
+
+		return wallabagoResponseAnnotationsMsg{
+			ID:          id,
+			Annotations: annotations,
+		}
+	}
+}
+
+// Callback for opening a URL in the default browser.
+func requestOpenURL(url string) tea.Cmd {
+	return func() tea.Msg {
+		if err := openLinkInBrowser(url); err != nil {
+			return wallabagoResponseErrorMsg{
+				message:        "Error:\n Couldn't open link in browser",
+				wallabagoError: err,
+			}
+		}
+
+		return walgotURLOpenedMsg(url)
+	}
+}
+
+// Callback for opening several URLs in the default browser.
+// Failing URLs don't stop the others, they are reported together.
+func requestOpenURLs(urls []string) tea.Cmd {
+	return func() tea.Msg {
+		msg := walgotURLsOpenedMsg{}
+		for _, url := range urls {
+			if err := openLinkInBrowser(url); err != nil {
+				log.Println("Couldn't open link in browser", url, err)
+				msg.Failed = append(msg.Failed, url)
+				continue
+			}
+			msg.Opened++
+		}
+
+		return msg
+	}
+}
+
+// Callback for copying a URL to clipboard.
+func requestCopyURL(url string) tea.Cmd {
+	return func() tea.Msg {
+		if err := copyLinkToClipboard(url); err != nil {
+			return wallabagoResponseErrorMsg{
+				message:        "Error:\n Couldn't copy link",
+				wallabagoError: err,
+			}
+		}
+
+		return walgotURLCopiedMsg(url)
+	}
+}
+
+// Callback for a desktop notification about new entries.
+// Failures are only logged, notifications are not essential.
+func requestNewEntriesNotification(nbNew int) tea.Cmd {
+	return func() tea.Msg {
+		if err := notify.Send("walgot", getNewEntriesText(nbNew)); err != nil {
+			log.Println("Couldn't send notification:", err)
+		}
+
+		return nil
+	}
+}
+
 // Callback for selecting entry in list:
 func selectEntryCommand(selectedRowID int) tea.Cmd {
 	return func() tea.Msg {
@@ -318,14 +1055,52 @@ func selectEntryCommand(selectedRowID int) tea.Cmd {
 	}
 }
 
+// Save state before quitting.
+func quitCommand(m *model) tea.Cmd {
+	if len(m.StateFile) > 0 {
+		err := saveState(m.StateFile, walgotState{
+			Unread:   m.Options.Filters.ReadState == "unread",
+			Starred:  m.Options.Filters.Starred,
+			Archived: m.Options.Filters.ReadState == "archived",
+			Public:   m.Options.Filters.Public,
+		})
+		if err != nil && m.DebugMode {
+			log.Println("Couldn't save state file", err)
+		}
+	}
+
+	// Abort running API calls:
+	if m.Cancel != nil {
+		m.Cancel()
+	}
+
+	return tea.Quit
+}
+
 // ** Model related methods ** //
 // Init method.
 func (m model) Init() tea.Cmd {
 	//wallabago.ReadConfig(m.WallabagConfig )
 
+	// Only cached entries are used offline:
+	if m.Offline {
+		return tea.Batch(
+			requestCachedEntries(m.CacheFile),
+			m.Spinner.Tick,
+		)
+	}
+	if m.Paginated {
+		return tea.Batch(
+			requestWallabagEntriesPage(m.ReloadCtx, m.CurrentPage, m.NbEntriesPerAPICall, m.Options.Filters, m.Options.Sorts),
+			m.Spinner.Tick,
+			autoRefreshCommand(m.AutoRefreshInterval),
+		)
+	}
+
 	return tea.Batch(
-		requestWallabagNbEntries,
+		requestWallabagNbEntries(m.ReloadCtx),
 		m.Spinner.Tick,
+		autoRefreshCommand(m.AutoRefreshInterval),
 	)
 }
 
@@ -334,20 +1109,86 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.DebugMode {
 		log.Println(fmt.Sprintf("Update message received, type: %T", msg), m.CurrentView)
 	}
+	// Entry being read may have been removed since (eg: queued delete, reload):
+	closeMissingEntry(&m)
 
 	if msg, ok := msg.(tea.KeyMsg); ok {
+		// Keys popup is closed by any key, help key also opens help:
+		if m.ShowKeysPopup {
+			m.ShowKeysPopup = false
+			if msg.String() != m.Keys["help"] {
+				return m, nil
+			}
+		}
+		// Any other key cancels a double key press:
+		if msg.String() != m.PendingKey {
+			m.PendingKey = ""
+		}
 		// C-c to kill the app.
 		if msg.String() == "ctrl+c" {
-			return m, tea.Quit
-		} else if msg.String() == "?" && !m.Reloading {
+			return m, quitCommand(&m)
+		} else if msg.String() == "esc" && (m.Reloading || m.AutoRefreshing && m.SelectedID == 0 && m.Dialog.Message == "" && m.CurrentView == m.BrowsingView) {
+			cancelReload(&m)
+			return m, clearUpdateMessageCmd()
+		} else if msg.String() == m.Keys["help"] && !m.Reloading {
 			m.CurrentView = "help"
 			return m, nil
+		} else if m.Offline && m.Dialog.Message == "" && m.CurrentView != "help" && isNetworkAction(msg.String(), m.Keys, m.SelectedID > 0) && !isQueueableAction(msg.String(), m.Keys, m.SelectedID > 0) {
+			m.UpdateMessage = "Not available in offline mode"
+			return m, clearUpdateMessageCmd()
+		} else if m.ReadOnly && m.Dialog.Message == "" && m.CurrentView != "help" && isMutatingAction(msg.String(), m.Keys, m.SelectedID > 0) {
+			m.UpdateMessage = "Not available in read-only mode"
+			return m, clearUpdateMessageCmd()
+		} else if msg.String() == m.Keys["keysPopup"] && m.Dialog.Message == "" && m.CurrentView != "help" && !m.Reloading {
+			m.ShowKeysPopup = true
+			return m, nil
+		} else if msg.String() == m.Keys["toggleStatusLine"] && m.Dialog.Message == "" {
+			m.ShowStatusLine = !m.ShowStatusLine
+			// Footer height may have changed:
+			windowSizeUpdate(&m)
+			return m, nil
 		}
 	}
 
+	// When resizing the window, sizes needs to change everywhere…
+	// Only the last size of a series of resizes is applied.
+	if v, ok := msg.(tea.WindowSizeMsg); ok {
+		m.TermSize = termSize{v.Width, v.Height}
+		// First size is applied right away:
+		if !m.Ready {
+			windowSizeUpdate(&m)
+			return m, nil
+		}
+		m.ResizeID++
+		return m, resizeCommand(m.ResizeID)
+	} else if v, ok := msg.(walgotResizeMsg); ok {
+		if int(v) == m.ResizeID {
+			windowSizeUpdate(&m)
+		}
+		return m, nil
+	} else if _, ok := msg.(walgotAutoRefreshMsg); ok {
+		next := autoRefreshCommand(m.AutoRefreshInterval)
+		// Not refreshed while reading or answering a dialog, next time maybe:
+		if m.Reloading || m.AutoRefreshing || m.SelectedID > 0 || m.Dialog.Message != "" {
+			return m, next
+		}
+		return m, tea.Batch(autoRefreshEntries(&m), next)
+	} else if v, ok := msg.(walgotPendingKeyTimeoutMsg); ok {
+		if int(v) == m.PendingKeyID {
+			m.PendingKey = ""
+		}
+		return m, nil
+	}
+
 	// Priority: Error > updates > entrySelection:
 	if v, ok := msg.(wallabagoResponseErrorMsg); ok {
+		// Calls of a canceled reload are expected to fail:
+		if errors.Is(v.wallabagoError, context.Canceled) {
+			return m, nil
+		}
 		m.Reloading = false
+		m.AutoRefreshing = false
+		m.RefreshingID = 0
 		if m.DebugMode {
 			log.Println("Wallabago error:")
 			log.Println(v.wallabagoError)
@@ -358,9 +1199,68 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// the model needs to be updated with refreshed entry:
 		updatedEntryInModel(&m, v.UpdatedEntry)
 		// To remove the update message after 3 seconds:
-		return m, tea.Tick(time.Second*5, func(t time.Time) tea.Msg {
-			return wallabagoResponseClearMsg(true)
-		})
+		return m, clearUpdateMessageCmd()
+	} else if v, ok := msg.(walgotChangeQueuedMsg); ok {
+		if v.Err != nil {
+			log.Println("Couldn't reach wallabag, change of entry", v.Change.ID, "is queued:", v.Err)
+		}
+		queuePendingChange(&m, v.Change)
+		m.UpdateMessage = getQueuedChangeText(v.Err == nil, len(m.PendingChanges))
+		return m, clearUpdateMessageCmd()
+	} else if v, ok := msg.(walgotChangesSentMsg); ok {
+		m.SendingChanges = false
+		// Changes of the previous profile:
+		if v.QueueFile != m.QueueFile {
+			return m, nil
+		}
+		m.PendingChanges = removeChanges(m.PendingChanges, v.Done)
+		savePendingChangesOf(&m)
+		// Entries changed on wallabag since, its version is kept:
+		for _, entry := range v.Conflicts {
+			refreshedEntryInModel(&m, entry)
+		}
+		m.UpdateMessage = getSentChangesText(v.Sent, len(v.Conflicts), len(m.PendingChanges))
+		return m, clearUpdateMessageCmd()
+	} else if v, ok := msg.(wallabagoResponseEntryRefreshMsg); ok {
+		m.RefreshingID = 0
+		refreshedEntryInModel(&m, v.Entry)
+		return m, clearUpdateMessageCmd()
+	} else if v, ok := msg.(wallabagoResponseAnnotationsMsg); ok {
+		// Entry may not be read anymore, annotations are kept for later:
+		if index := getSelectedEntryIndex(m.Entries, v.ID); index >= 0 {
+			m.Entries[index].Annotations = v.Annotations
+		}
+		if m.SelectedID == v.ID {
+			refreshDetailViewport(&m)
+		}
+		return m, nil
+	} else if v, ok := msg.(wallabagoResponseServerCountMsg); ok {
+		m.ServerCount = int(v)
+		m.UpdateMessage = ""
+		return m, nil
+	} else if _, ok := msg.(walgotURLOpenedMsg); ok {
+		m.UpdateMessage = "Link opened in browser"
+		return m, clearUpdateMessageCmd()
+	} else if v, ok := msg.(walgotURLsOpenedMsg); ok {
+		if len(v.Failed) > 0 {
+			m.Dialog.Message = fmt.Sprintf("%d links opened in browser, couldn't open:\n\n%s", v.Opened, strings.Join(v.Failed, "\n"))
+			return m, nil
+		}
+		m.UpdateMessage = fmt.Sprintf("%d links opened in browser", v.Opened)
+		return m, clearUpdateMessageCmd()
+	} else if _, ok := msg.(walgotURLCopiedMsg); ok {
+		m.UpdateMessage = "URL copied"
+		return m, clearUpdateMessageCmd()
+	} else if v, ok := msg.(walgotExportProgressMsg); ok {
+		// Export can run while browsing, whatever the current view:
+		m.UpdateMessage = fmt.Sprintf("Exporting articles… %d/%d", v.Done, v.Total)
+		return m, waitForWallabagEntries(v.messages)
+	} else if v, ok := msg.(walgotExportDoneMsg); ok {
+		m.UpdateMessage = fmt.Sprintf("%d articles exported to %s", v.Exported, v.Directory)
+		if v.Failed > 0 {
+			m.UpdateMessage += fmt.Sprintf(", %d failed (see log file)", v.Failed)
+		}
+		return m, clearUpdateMessageCmd()
 	} else if v, ok := msg.(wallabagoResponseClearMsg); ok && bool(v) {
 		// Clear update message
 		m.UpdateMessage = ""
@@ -369,7 +1269,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.SelectedID = int(v)
 	}
 
-	// Priority order: dialog > help > detail > list.
+	// Reload messages are managed by the list view whatever the current view,
+	// retrieval of entries would be blocked otherwise (eg: quit dialog opened)
+	// and an automatic refresh may end while reading an entry:
+	switch msg.(type) {
+	case wallabagoResponseNbEntitiesMsg, wallabagoLoadProgressMsg, wallabagoResponseEntitiesMsg, wallabagoResponsePartialEntitiesMsg, wallabagoResponsePageMsg:
+		return updateListView(msg, m)
+	}
+
+	// Priority order: dialog > help > detail > logs > tags > profiles > grouped > list.
 	if m.Dialog.Message != "" {
 		return updateDialogView(msg, &m)
 	} else if m.CurrentView == "help" {
@@ -379,11 +1287,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Now send to the right sub-update function:
 	if m.SelectedID > 0 {
 		return updateEntryView(msg, &m)
+	} else if m.CurrentView == "logs" {
+		return updateLogsView(msg, m)
+	} else if m.CurrentView == "tags" {
+		return updateTagsView(msg, m)
+	} else if m.CurrentView == "profiles" {
+		return updateProfilesView(msg, m)
+	} else if m.CurrentView == "grouped" {
+		return updateGroupedView(msg, m)
 	}
 	return updateListView(msg, m)
 }
 
 // View method.
 func (m model) View() string {
-	return fmt.Sprintf("%s\n%s\n%s", m.headerView(), m.mainView(), m.footerView())
+	if m.Ready && isTermTooSmall(m.TermSize) {
+		return termTooSmallView(m)
+	}
+	main := m.mainView()
+	if m.ShowKeysPopup {
+		main = overlayBottom(main, m.keysPopupView())
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s", m.headerView(), main, m.footerView())
 }
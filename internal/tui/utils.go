@@ -2,16 +2,28 @@ package tui
 
 import (
 	"errors"
+	"fmt"
+	"math/rand"
 	"net/url"
 	"os/exec"
 	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
+	"git.bacardi55.io/bacardi55/walgot/internal/clipboard"
+	"git.bacardi55.io/bacardi55/walgot/internal/util"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
 	"github.com/Strubbl/wallabago/v7"
-	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/k3a/html2text"
+	"github.com/muesli/reflow/truncate"
 	"github.com/muesli/reflow/wordwrap"
 	"github.com/muesli/reflow/wrap"
 )
@@ -41,7 +53,114 @@ func openLinkInBrowser(url string) error {
 // Copy link.
 // TODO: test on macOS or windows…
 func copyLinkToClipboard(url string) error {
-	return clipboard.WriteAll(url)
+	return clipboard.Write(url)
+}
+
+// Retrieve a URL from clipboard, empty string if it doesn't contain one.
+func getURLFromClipboard() string {
+	content, err := clipboard.Read()
+	if err != nil {
+		return ""
+	}
+	content = strings.TrimSpace(content)
+	if !isValidURL(content) {
+		return ""
+	}
+	return content
+}
+
+// Retrieve the selected row of a table with nbRows rows, nil if there is none.
+// Table doesn't expose its rows, their number is kept when they are set.
+func getSelectedRow(t table.Model, nbRows int) table.Row {
+	if t.Cursor() < 0 || t.Cursor() >= nbRows {
+		return nil
+	}
+
+	return t.SelectedRow()
+}
+
+// Retrieve the entry ID of the selected row in table, 0 if there is none.
+func getSelectedRowID(t table.Model, nbRows int) int {
+	row := getSelectedRow(t, nbRows)
+	if len(row) == 0 {
+		return 0
+	}
+	id, _ := strconv.Atoi(row[0])
+
+	return id
+}
+
+// Move table cursor to the given position, scrolling the table to display it.
+func setTableCursor(t *table.Model, position int) {
+	t.SetCursor(position)
+	// Moving by 0 rows scrolls the table to the cursor:
+	t.MoveUp(0)
+	t.MoveDown(0)
+}
+
+// Retrieve the position of an entry in table rows and the ID of the entry
+// offset rows away from it (eg: 1 for next one, -1 for previous one).
+// Position is -1 if the entry isn't in rows, ID is 0 if there is no such entry.
+func getAdjacentRowID(rows []table.Row, id, offset int) (int, int) {
+	for i := range rows {
+		if rows[i][0] != strconv.Itoa(id) {
+			continue
+		}
+		if i+offset < 0 || i+offset >= len(rows) {
+			return i, 0
+		}
+		adjacentID, _ := strconv.Atoi(rows[i+offset][0])
+		return i + offset, adjacentID
+	}
+
+	return -1, 0
+}
+
+// Pick a random entry to read, unread entries first, 0 if there is no entry.
+func getRandomEntryID(entries []wallabago.Item, rnd *rand.Rand) int {
+	candidates := []int{}
+	for i := range entries {
+		if entries[i].IsArchived == 0 {
+			candidates = append(candidates, entries[i].ID)
+		}
+	}
+	if len(candidates) == 0 {
+		for i := range entries {
+			candidates = append(candidates, entries[i].ID)
+		}
+	}
+	if len(candidates) == 0 {
+		return 0
+	}
+
+	return candidates[rnd.Intn(len(candidates))]
+}
+
+// Return the position in table rows to jump to, from a line number (starting at 1)
+// or an entry ID prefixed with "#" (eg: "#42").
+func getJumpPosition(rows []table.Row, input string) (int, error) {
+	input = strings.TrimSpace(input)
+	if strings.HasPrefix(input, "#") {
+		id, err := strconv.Atoi(strings.TrimSpace(input[1:]))
+		if err != nil || id <= 0 {
+			return -1, errors.New("Invalid entry ID " + input)
+		}
+		position, _ := getAdjacentRowID(rows, id, 0)
+		if position < 0 {
+			return -1, errors.New("Entry " + input + " not found in the list")
+		}
+		return position, nil
+	}
+
+	line, err := strconv.Atoi(input)
+	if err != nil {
+		return -1, errors.New("Invalid line number " + input)
+	}
+	if line < 1 || line > len(rows) {
+		return -1, fmt.Errorf("Line %d not found, the list has %d lines", line, len(rows))
+	}
+
+	return line - 1, nil
 }
 
 // Retrieve index of the selected entry in model.Entries
@@ -56,16 +175,670 @@ func getSelectedEntryIndex(entries []wallabago.Item, id int) int {
 	return entryIndex
 }
 
+// Retrieve the public link of an entry, empty if there is none.
+func getEntryPublicURL(entry *wallabago.Item, wallabagURL string) string {
+	if !entry.IsPublic || entry.UID == "" {
+		return ""
+	}
+
+	return wallabagURL + "/share/" + entry.UID
+}
+
+// Generate the metadata of an entry, one field per line, for the reading view panel.
+func getEntryMetadataText(entry *wallabago.Item, dateFormat, wallabagURL string) string {
+	domain := entry.DomainName
+	if domain == "" {
+		domain = noDomainLabel
+	}
+	tags := "none"
+	if labels := getEntryTagLabels(entry); len(labels) > 0 {
+		tags = strings.Join(labels, ", ")
+	}
+	status := []string{"unread"}
+	if entry.IsArchived == 1 {
+		status = []string{"archived"}
+	}
+	if entry.IsStarred == 1 {
+		status = append(status, "starred")
+	}
+	if entry.IsPublic {
+		status = append(status, "public")
+	}
+
+	lines := []string{
+		"URL: " + entry.URL,
+		"Domain: " + domain,
+		"Created: " + formatEntryDate(entry.CreatedAt, dateFormat, false),
+		"Updated: " + formatEntryDate(entry.UpdatedAt, dateFormat, false),
+		"Est. read: " + formatReadingTime(entry.ReadingTime),
+		"Tags: " + tags,
+		"Status: " + strings.Join(status, ", "),
+	}
+	if publicURL := getEntryPublicURL(entry, wallabagURL); publicURL != "" {
+		lines = append(lines, "Public link: "+publicURL)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Generate the confirmation message after an entry update.
+func getEntryUpdateMessage(previous, updated wallabago.Item, wallabagURL string) string {
+	if previous.IsPublic != updated.IsPublic {
+		if !updated.IsPublic {
+			return "Entry is not public anymore"
+		}
+		if publicURL := getEntryPublicURL(&updated, wallabagURL); publicURL != "" {
+			return "Entry is now public: " + publicURL
+		}
+		return "Entry is now public (no public link returned by wallabag)"
+	}
+	if previous.IsArchived != updated.IsArchived {
+		if updated.IsArchived == 1 {
+			return "Entry archived"
+		}
+		return "Entry marked as unread"
+	}
+	if previous.IsStarred != updated.IsStarred {
+		if updated.IsStarred == 1 {
+			return "Entry starred"
+		}
+		return "Entry unstarred"
+	}
+	if labels := getEntryTagLabels(&updated); strings.Join(getEntryTagLabels(&previous), ",") != strings.Join(labels, ",") {
+		if len(labels) == 0 {
+			return "All tags removed"
+		}
+		return "Tags updated: " + strings.Join(labels, ", ")
+	}
+
+	return "Entry has been updated"
+}
+
+// Retrieve the labels of the entry tags.
+func getEntryTagLabels(entry *wallabago.Item) []string {
+	labels := []string{}
+	for _, t := range entry.Tags {
+		labels = append(labels, t.Label)
+	}
+
+	return labels
+}
+
+// Number of entries with a tag.
+type walgotTagCount struct {
+	Label string
+	Count int
+}
+
+// Count entries per tag, sorted by label.
+// Tags differing only by case are counted together, with the first label found.
+func getTagCounts(entries []wallabago.Item) []walgotTagCount {
+	tags := []walgotTagCount{}
+	indexes := map[string]int{}
+	for i := range entries {
+		seen := map[string]bool{}
+		for _, label := range getEntryTagLabels(&entries[i]) {
+			key := strings.ToLower(label)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			index, ok := indexes[key]
+			if !ok {
+				index = len(tags)
+				indexes[key] = index
+				tags = append(tags, walgotTagCount{Label: label})
+			}
+			tags[index].Count++
+		}
+	}
+
+	sort.Slice(tags, func(a, b int) bool {
+		return strings.ToLower(tags[a].Label) < strings.ToLower(tags[b].Label)
+	})
+
+	return tags
+}
+
+// Parse comma separated tags, empty and duplicated (case insensitive) ones are ignored.
+func parseTagsInput(input string) []string {
+	labels := []string{}
+	seen := map[string]bool{}
+	for _, label := range strings.Split(input, ",") {
+		label = strings.TrimSpace(label)
+		if label == "" || seen[strings.ToLower(label)] {
+			continue
+		}
+		seen[strings.ToLower(label)] = true
+		labels = append(labels, label)
+	}
+
+	return labels
+}
+
+// Check if the entry has the given tag (case insensitive).
+func hasTag(entry *wallabago.Item, tag string) bool {
+	for _, t := range entry.Tags {
+		if strings.EqualFold(t.Label, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Format the reading time (in minutes) of an entry.
+func formatReadingTime(minutes int) string {
+	if minutes <= 0 {
+		return "< 1 min"
+	} else if minutes < 60 {
+		return strconv.Itoa(minutes) + " min"
+	}
+
+	return fmt.Sprintf("%dh%02d", minutes/60, minutes%60)
+}
+
+// Sum the reading time (in minutes) of entries matching filters.
+// Entries without reading time (0, eg: empty content) are ignored.
+func getTotalReadingTime(entries []wallabago.Item, filters walgotTableFilters) int {
+	total := 0
+	for i := range entries {
+		if entries[i].ReadingTime > 0 && isEntryMatchingFilters(&entries[i], filters) {
+			total += entries[i].ReadingTime
+		}
+	}
+
+	return total
+}
+
 // Retrieve the article content, in clean and wrap text.
-func getSelectedEntryContent(entries []wallabago.Item, index, maxWidth int) string {
-	content := getContentForViewport(entries[index].Content)
+// The "markdown" renderer displays headings, lists and links with styles,
+// plain text is used if it fails.
+func getSelectedEntryContent(entry *wallabago.Item, options walgotContentOptions) string {
+	if options.Renderer == "markdown" {
+		if content, err := getMarkdownContentForViewport(entry.Content, options.WrapWidth, options.LinkReferences, options.Trim); err == nil {
+			return content
+		}
+	}
+	content := trimContent(getContentForViewport(entry.Content, options.LinkReferences), options.Trim)
+
+	return wrap.String(wordwrap.String(content, options.WrapWidth), options.WrapWidth)
+}
+
+// ANSI escape sequences of styled text (eg: markdown rendering).
+var ansiSequenceRegexp = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// Term highlighted in the reading view: the search in the article,
+// or else the list search, or else the search on wallabag.
+func getHighlightTerm(articleSearch string, filters walgotTableFilters) string {
+	if articleSearch != "" {
+		return articleSearch
+	}
+	if filters.Search != "" {
+		return filters.Search
+	}
+
+	return filters.ServerSearch
+}
+
+// Lines of the wrapped content matching term (case insensitive), styles are ignored.
+func getMatchLines(content, term string) []int {
+	var lines []int
+	if term == "" {
+		return lines
+	}
+	term = strings.ToLower(term)
+	for i, line := range strings.Split(ansiSequenceRegexp.ReplaceAllString(content, ""), "\n") {
+		if strings.Contains(strings.ToLower(line), term) {
+			lines = append(lines, i)
+		}
+	}
+
+	return lines
+}
+
+// Index of the first match from the given line, the first match if there is none after.
+func getFirstMatchFrom(lines []int, line int) int {
+	for i, l := range lines {
+		if l >= line {
+			return i
+		}
+	}
+
+	return 0
+}
+
+// Matches of the search in the article, for the reading view footer.
+func getArticleSearchText(search walgotArticleSearch) string {
+	if search.Term == "" {
+		return ""
+	} else if len(search.Lines) == 0 {
+		return "no match"
+	}
+
+	return fmt.Sprintf("match %d/%d", search.Current+1, len(search.Lines))
+}
+
+// Highlight matches of term (case insensitive) in the already wrapped text.
+// Styled parts of text are kept, matches split by a style or a line break
+// are not highlighted.
+func highlightMatches(text, term string, highlight func(string) string) string {
+	if term == "" {
+		return text
+	}
+
+	var result strings.Builder
+	last := 0
+	for _, loc := range ansiSequenceRegexp.FindAllStringIndex(text, -1) {
+		result.WriteString(highlightPlainMatches(text[last:loc[0]], term, highlight))
+		result.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	result.WriteString(highlightPlainMatches(text[last:], term, highlight))
+
+	return result.String()
+}
+
+// Highlight matches of term in text without style.
+// Overlapping and adjacent matches are highlighted together.
+func highlightPlainMatches(text, term string, highlight func(string) string) string {
+	runes := []rune(text)
+	termRunes := []rune(strings.ToLower(term))
+	if len(termRunes) == 0 || len(runes) < len(termRunes) {
+		return text
+	}
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	matched := make([]bool, len(runes))
+	for i := 0; i+len(termRunes) <= len(runes); i++ {
+		if string(lower[i:i+len(termRunes)]) == string(termRunes) {
+			for j := i; j < i+len(termRunes); j++ {
+				matched[j] = true
+			}
+		}
+	}
+
+	var result strings.Builder
+	for i := 0; i < len(runes); {
+		j := i
+		for j < len(runes) && matched[j] == matched[i] {
+			j++
+		}
+		if matched[i] {
+			result.WriteString(highlight(string(runes[i:j])))
+		} else {
+			result.WriteString(string(runes[i:j]))
+		}
+		i = j
+	}
+
+	return result.String()
+}
+
+// Generate annotations text for the detail view, empty if there is none.
+// Highlighted quotes are prefixed with "> ", followed by their note if any.
+func getAnnotationsText(annotations []wallabago.Annotation, wrapWidth int) string {
+	if len(annotations) == 0 {
+		return ""
+	}
+
+	text := "Annotations:"
+	for _, a := range annotations {
+		quote := wrap.String(wordwrap.String(strings.TrimSpace(a.Quote), wrapWidth-2), wrapWidth-2)
+		text += "\n\n> " + strings.ReplaceAll(quote, "\n", "\n> ")
+		if note := strings.TrimSpace(a.Text); note != "" {
+			text += "\n" + wrap.String(wordwrap.String(note, wrapWidth), wrapWidth)
+		}
+	}
+
+	return text
+}
+
+// Generate a line of the given width, filled proportionally to the read percent (0 to 1).
+func getReadProgressLine(width int, percent float64) string {
+	if percent < 0 {
+		percent = 0
+	} else if percent > 1 {
+		percent = 1
+	}
+	filled := int(float64(width) * percent)
+
+	return strings.Repeat("━", filled) + strings.Repeat("─", width-filled)
+}
+
+// Calculate reading view widths (viewport and content wrapping).
+// A readingWidth of 0 or less means auto: 80 columns viewport, content wrapped at 72.
+// Widths are reduced if the terminal isn't large enough.
+func getReadingWidths(readingWidth, termWidth int) (int, int) {
+	viewportWidth, wrapWidth := 80, 72
+	if readingWidth > 0 {
+		viewportWidth, wrapWidth = readingWidth, readingWidth
+	}
+
+	if termWidth < viewportWidth {
+		viewportWidth = termWidth
+	}
+	if termWidth < wrapWidth {
+		wrapWidth = termWidth - 2
+	}
+	// Tiny terminals, widths must stay usable:
+	if viewportWidth < 1 {
+		viewportWidth = 1
+	}
+	if wrapWidth < 1 {
+		wrapWidth = 1
+	}
+
+	return viewportWidth, wrapWidth
+}
+
+// Number of rows of half a page of the given height, at least one.
+func getHalfPage(height int) int {
+	if height < 2 {
+		return 1
+	}
+
+	return height / 2
+}
+
+// Number of rows jumped by page up / page down, pageJumpRows if set,
+// otherwise the given visible height (at least one).
+func getPageJump(pageJumpRows, height int) int {
+	if pageJumpRows > 0 {
+		return pageJumpRows
+	}
+	if height < 1 {
+		return 1
+	}
+
+	return height
+}
+
+// Height of the main view components (table, viewports), from the height left
+// by header and footer minus the lines used around them. At least one line,
+// even on tiny terminals.
+func getMainHeight(height, used int) int {
+	if height-used < 1 {
+		return 1
+	}
+
+	return height - used
+}
+
+// Check if the terminal is too small to display views.
+func isTermTooSmall(size termSize) bool {
+	return size.Width < minTermWidth || size.Height < minTermHeight
+}
+
+// Calculate list view width.
+// A listWidth of 0 or less means the whole terminal width,
+// or a part of it on wide terminals if the preview pane is displayed.
+func getListWidth(listWidth, termWidth int, preview bool) int {
+	if listWidth <= 0 || listWidth > termWidth {
+		if preview && termWidth >= previewMinTermWidth {
+			return termWidth * 3 / 5
+		}
+		return termWidth
+	}
+
+	return listWidth
+}
+
+// Calculate the preview pane width, next to the list.
+// The pane uses the width left by the list, 0 if it's too narrow.
+func getPreviewWidth(listWidth, termWidth int, preview bool) int {
+	if !preview {
+		return 0
+	}
+	width := termWidth - getListWidth(listWidth, termWidth, preview)
+	if width < previewMinWidth {
+		return 0
+	}
+
+	return width
+}
+
+// Generate the preview of an entry: its title and the first lines of its content,
+// wrapped at the given width and limited to height lines.
+func getPreviewText(entry *wallabago.Item, width, height int) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	title := wrap.String(wordwrap.String(entry.Title, width), width)
+	lines := strings.Split(title, "\n")
+	lines = append(lines, "")
+	content := getContentForViewport(entry.Content, false)
+	content = wrap.String(wordwrap.String(content, width), width)
+	for _, line := range strings.Split(content, "\n") {
+		// Consecutive empty lines are merged:
+		if strings.TrimSpace(line) == "" && lines[len(lines)-1] == "" {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \r"))
+		if len(lines) >= height {
+			break
+		}
+	}
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Generate the text of the keys popup, lines are displayed in columns (top to bottom)
+// of at most maxRows lines. Lines that don't fit are replaced by "…".
+func getKeysPopupText(lines []string, width, maxRows int) string {
+	if width <= 0 || maxRows <= 0 || len(lines) == 0 {
+		return ""
+	}
+	columnWidth := 40
+	if width < columnWidth {
+		columnWidth = width
+	}
+	columns := width / columnWidth
+	rows := (len(lines) + columns - 1) / columns
+	if rows > maxRows {
+		rows = maxRows
+		lines = append(append([]string{}, lines[:rows*columns-1]...), "…")
+	}
+
+	text := make([]string, rows)
+	for i, line := range lines {
+		cell := truncate.StringWithTail(line, uint(columnWidth-2), "…")
+		// Last column isn't padded:
+		if i+rows < len(lines) {
+			cell += strings.Repeat(" ", columnWidth-lipgloss.Width(cell))
+		}
+		text[i%rows] += cell
+	}
+
+	return strings.Join(text, "\n")
+}
+
+// Generate the text of the logs view, log lines are wrapped at the given width.
+func getLogsText(lines []string, width int) string {
+	wrapped := []string{}
+	for _, line := range lines {
+		wrapped = append(wrapped, wrap.String(wordwrap.String(line, width), width))
+	}
+
+	return strings.Join(wrapped, "\n")
+}
+
+// Return the beginning of the text content of an entry, on a single line,
+// truncated at a word boundary to at most maxLength characters.
+func getExcerpt(contentHTML string, maxLength int) string {
+	text := strings.Join(strings.Fields(getContentForViewport(contentHTML, false)), " ")
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+	// Next character is kept to know if the cut is on a word boundary:
+	cut := string(runes[:maxLength+1])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		return cut[:i] + "…"
+	}
+
+	return string(runes[:maxLength]) + "…"
+}
+
+// Return the number of entries on server when checked, with the difference
+// with the loaded ones. Nothing is returned if it wasn't checked (0).
+func getServerCountText(serverCount, loaded int) string {
+	if serverCount <= 0 {
+		return ""
+	}
+	text := fmt.Sprintf(" (%d on server", serverCount)
+	if serverCount > loaded {
+		text += fmt.Sprintf(", %d new", serverCount-loaded)
+	} else if serverCount < loaded {
+		text += fmt.Sprintf(", %d removed", loaded-serverCount)
+	}
+
+	return text + ")"
+}
+
+// Return the message about the results of a wallabag search, with the number
+// of them actually shown when some are hidden by the other filters.
+func getServerSearchMessage(nbMatches, nbShown int, term string) string {
+	text := fmt.Sprintf("%d matches on wallabag for %s", nbMatches, term)
+	if nbShown < nbMatches {
+		text += fmt.Sprintf(", %d shown with current filters", nbShown)
+	}
+
+	return text
+}
+
+// Count entries whose ID has not been seen.
+func countUnseenEntries(entries []wallabago.Item, seen map[int]bool) int {
+	count := 0
+	for _, e := range entries {
+		if !seen[e.ID] {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Text of the new entries notification.
+func getNewEntriesText(nbNew int) string {
+	if nbNew == 1 {
+		return "1 new article on wallabag"
+	}
+
+	return strconv.Itoa(nbNew) + " new articles on wallabag"
+}
+
+// Message displayed when a change is queued, offline or because wallabag
+// couldn't be reached.
+func getQueuedChangeText(offline bool, nbPending int) string {
+	text := "Couldn't reach wallabag, change will be sent later"
+	if offline {
+		text = "Offline, change will be sent to wallabag later"
+	}
+
+	return fmt.Sprintf("%s (%d pending)", text, nbPending)
+}
+
+// Message displayed when pending changes have been sent.
+func getSentChangesText(sent, conflicts, pending int) string {
+	if sent == 0 && conflicts == 0 {
+		return fmt.Sprintf("Couldn't reach wallabag, %d changes still pending", pending)
+	}
+	text := fmt.Sprintf("%d pending changes sent to wallabag", sent)
+	if conflicts > 0 {
+		text += fmt.Sprintf(", %d changed on wallabag since (wallabag version kept)", conflicts)
+	}
+	if pending > 0 {
+		text += fmt.Sprintf(", %d still pending", pending)
+	}
 
-	w := 72
-	if maxWidth < w {
-		w = maxWidth - 2
+	return text
+}
+
+// Return the loading message, with the number of entries retrieved so far if any.
+// Total is unknown (0) until wallabag sent it.
+func getLoadingText(total, loaded int) string {
+	if total <= 0 {
+		return "Loading all entries from wallabag…"
+	}
+	if loaded <= 0 {
+		return "Loading all " + strconv.Itoa(total) + " entries from wallabag…"
 	}
 
-	return wrap.String(wordwrap.String(content, w), w)
+	return "Loaded " + strconv.Itoa(loaded) + " / " + strconv.Itoa(total) + " entries from wallabag…"
+}
+
+// Sort entries in place, ties are sorted by ID.
+func sortEntries(entries []wallabago.Item, sorts walgotTableSorts) {
+	less := func(a, b *wallabago.Item) bool {
+		switch sorts.Field {
+		case "updated":
+			return getEntryTime(a.UpdatedAt).Before(getEntryTime(b.UpdatedAt))
+		case "title":
+			return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+		case "reading":
+			return a.ReadingTime < b.ReadingTime
+		default:
+			return getEntryTime(a.CreatedAt).Before(getEntryTime(b.CreatedAt))
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := &entries[i], &entries[j]
+		if sorts.Order != "asc" {
+			a, b = b, a
+		}
+		if less(a, b) != less(b, a) {
+			return less(a, b)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// Retrieve the index of the table column at the given x position, -1 if none.
+// Each column has a 1 character padding on both sides.
+func getTableColumnAt(columns []table.Column, x int) int {
+	start := 0
+	for i, c := range columns {
+		if c.Width == 0 {
+			continue
+		}
+		end := start + c.Width + 2
+		if x >= start && x < end {
+			return i
+		}
+		start = end
+	}
+
+	return -1
+}
+
+// Retrieve time from wallabago time, zero time if not set.
+func getEntryTime(t *wallabago.WallabagTime) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	return t.Time
+}
+
+// Format an entry date with the given layout, or relatively to now (eg: "2 days ago").
+// Empty string if there is no date.
+func formatEntryDate(t *wallabago.WallabagTime, layout string, relative bool) string {
+	if t == nil || t.Time.IsZero() {
+		return ""
+	}
+	if relative {
+		return util.RelativeTime(t.Time, time.Now())
+	}
+
+	return t.Time.Format(layout)
 }
 
 // Calculate the number of API call needed to retrieve all articles.
@@ -77,14 +850,318 @@ func getRequiredNbAPICalls(nbArticles, limitArticleByAPICall int) int {
 		limitArticleByAPICall = nbArticles
 	}
 
-	nbCalls := 1
-	if nbArticles > limitArticleByAPICall {
-		nbCalls = nbArticles / limitArticleByAPICall
-		if float64(nbCalls) < float64(nbArticles)/float64(limitArticleByAPICall) {
-			nbCalls++
+	// Integer ceiling of nbArticles / limitArticleByAPICall:
+	return (nbArticles-1)/limitArticleByAPICall + 1
+}
+
+// Retrieve profile names, the default profile ("") first and others sorted.
+func getProfileNames(profileCredentials map[string]string) []string {
+	names := []string{}
+	for name := range profileCredentials {
+		if name != "" {
+			names = append(names, name)
 		}
 	}
-	return nbCalls
+	sort.Strings(names)
+	if _, ok := profileCredentials[""]; ok {
+		names = append([]string{""}, names...)
+	}
+
+	return names
+}
+
+// Label of a profile, in the profiles view.
+func getProfileLabel(profile string) string {
+	if profile == "" {
+		return defaultProfileLabel
+	}
+	return profile
+}
+
+// Profile name from its label, in the profiles view.
+func getProfileName(label string) string {
+	if label == defaultProfileLabel {
+		return ""
+	}
+	return label
+}
+
+// Retrieve the keybinds hint displayed in the footer of the given view.
+func getFooterHint(view string, keys walgotKeys) string {
+	switch view {
+	case "dialog":
+		return "enter: confirm -- esc: cancel"
+	case "help":
+		return keys["quit"] + " / esc: back"
+	case "detail":
+		return fmt.Sprintf(
+			"%s / %s: next / previous -- %s: archive -- %s: star -- %s: back -- %s: help",
+			keys["nextEntry"],
+			keys["previousEntry"],
+			keys["toggleArchive"],
+			keys["toggleStar"],
+			keys["quit"],
+			keys["help"],
+		)
+	case "profiles":
+		return fmt.Sprintf("%s: switch profile -- %s / esc: back", keys["select"], keys["quit"])
+	case "logs":
+		return fmt.Sprintf("%s: show new lines -- %s / esc: back", keys["reload"], keys["quit"])
+	case "tags":
+		return fmt.Sprintf("%s: filter by tag -- %s / esc: back -- %s: help", keys["select"], keys["quit"], keys["help"])
+	case "grouped":
+		return fmt.Sprintf(
+			"%s / → / ←: expand / collapse or read -- %s / esc: back -- %s: help",
+			keys["select"],
+			keys["quit"],
+			keys["help"],
+		)
+	default:
+		return fmt.Sprintf(
+			"%s: reload -- %s: read state -- Toggles: %s: starred, %s: public -- %s: help",
+			keys["reload"],
+			keys["cycleReadState"],
+			keys["filterStarred"],
+			keys["filterPublic"],
+			keys["help"],
+		)
+	}
+}
+
+// Read state filter from unread and archived flags, unread wins if both are set.
+func getReadState(unread, archived bool) string {
+	if unread {
+		return "unread"
+	}
+	if archived {
+		return "archived"
+	}
+
+	return ""
+}
+
+// Next read state filter when cycling: unread, archived, then all.
+func getNextReadState(readState string) string {
+	switch readState {
+	case "unread":
+		return "archived"
+	case "archived":
+		return ""
+	default:
+		return "unread"
+	}
+}
+
+// Label of a read state filter.
+func getReadStateLabel(readState string) string {
+	switch readState {
+	case "unread":
+		return "Unread"
+	case "archived":
+		return "Archived"
+	default:
+		return "All"
+	}
+}
+
+// Check if an entry matches the given filters.
+func isEntryMatchingFilters(entry *wallabago.Item, filters walgotTableFilters) bool {
+	// Public filter:
+	if filters.Public && !entry.IsPublic {
+		return false
+	}
+	// Read state filter:
+	if filters.ReadState == "unread" && entry.IsArchived != 0 {
+		return false
+	}
+	if filters.ReadState == "archived" && entry.IsArchived != 1 {
+		return false
+	}
+	// Starred filter:
+	if filters.Starred && entry.IsStarred != 1 {
+		return false
+	}
+	// Search filter:
+	if filters.Search != "" &&
+		!containsI(entry.Title, filters.Search) &&
+		!containsI(entry.DomainName, filters.Search) {
+		return false
+	}
+	// Tag filter:
+	if filters.Tag != "" && !hasTag(entry, filters.Tag) {
+		return false
+	}
+	// Server search filter:
+	if filters.ServerSearch != "" && !filters.ServerSearchIDs[entry.ID] {
+		return false
+	}
+	// Date range filter:
+	if !isEntryInDateRange(entry, filters) {
+		return false
+	}
+
+	return true
+}
+
+// Check if the created (or updated) date of an entry is in the date range of filters.
+func isEntryInDateRange(entry *wallabago.Item, filters walgotTableFilters) bool {
+	if filters.Since.IsZero() && filters.Until.IsZero() {
+		return true
+	}
+	date := entry.CreatedAt
+	if filters.DateField == "updated" {
+		date = entry.UpdatedAt
+	}
+	if date == nil || date.IsZero() {
+		return false
+	}
+	if !filters.Since.IsZero() && date.Before(filters.Since) {
+		return false
+	}
+	if !filters.Until.IsZero() && !date.Before(filters.Until) {
+		return false
+	}
+
+	return true
+}
+
+// Date range relative to now, eg: "7d" (days, weeks, months or years).
+var relativeDateRangeRegexp = regexp.MustCompile(`^(\d+)([dwmy])$`)
+
+// Parse a date range filter: a relative duration until now (eg: "7d", "2w", "3m" or "1y"),
+// or dates as "2024-01-01..2024-02-01" (both dates included, one of them can be omitted).
+// Entries are filtered on their created date, or updated date with an "updated:" prefix.
+// Returns the date field, then the start and the (excluded) end of the range, zero if not set.
+func parseDateRange(input string, now time.Time) (string, time.Time, time.Time, error) {
+	input = strings.TrimSpace(input)
+	field := "created"
+	if strings.HasPrefix(input, "updated:") {
+		field = "updated"
+		input = strings.TrimSpace(strings.TrimPrefix(input, "updated:"))
+	} else if strings.HasPrefix(input, "created:") {
+		input = strings.TrimSpace(strings.TrimPrefix(input, "created:"))
+	}
+
+	if matches := relativeDateRangeRegexp.FindStringSubmatch(input); matches != nil {
+		n, _ := strconv.Atoi(matches[1])
+		switch matches[2] {
+		case "d":
+			return field, now.AddDate(0, 0, -n), time.Time{}, nil
+		case "w":
+			return field, now.AddDate(0, 0, -7*n), time.Time{}, nil
+		case "m":
+			return field, now.AddDate(0, -n, 0), time.Time{}, nil
+		default:
+			return field, now.AddDate(-n, 0, 0), time.Time{}, nil
+		}
+	}
+
+	start, end := input, ""
+	if i := strings.Index(input, ".."); i >= 0 {
+		start, end = strings.TrimSpace(input[:i]), strings.TrimSpace(input[i+2:])
+		if start == "" && end == "" {
+			return field, time.Time{}, time.Time{}, errors.New("Invalid date range " + input)
+		}
+	}
+	var since, until time.Time
+	var err error
+	if start != "" {
+		if since, err = time.ParseInLocation("2006-01-02", start, now.Location()); err != nil {
+			return field, time.Time{}, time.Time{}, errors.New("Invalid date " + start)
+		}
+	}
+	if end != "" {
+		if until, err = time.ParseInLocation("2006-01-02", end, now.Location()); err != nil {
+			return field, time.Time{}, time.Time{}, errors.New("Invalid date " + end)
+		}
+		// End date is included:
+		until = until.AddDate(0, 0, 1)
+	}
+	if !since.IsZero() && !until.IsZero() && !since.Before(until) {
+		return field, time.Time{}, time.Time{}, errors.New("Invalid date range " + input + ", end is before start")
+	}
+
+	return field, since, until, nil
+}
+
+// Retrieve entries matching the given filters.
+func getFilteredEntries(entries []wallabago.Item, filters walgotTableFilters) []wallabago.Item {
+	filtered := []wallabago.Item{}
+	for i := range entries {
+		if isEntryMatchingFilters(&entries[i], filters) {
+			filtered = append(filtered, entries[i])
+		}
+	}
+
+	return filtered
+}
+
+// Retrieve original URLs of the selected entries, in entries order.
+func getMarkedEntriesURLs(entries []wallabago.Item, marked map[int]bool) []string {
+	var urls []string
+	for i := range entries {
+		if marked[entries[i].ID] {
+			urls = append(urls, entries[i].URL)
+		}
+	}
+
+	return urls
+}
+
+// Check if the given field ("archive", "star" or "public") is set on an entry.
+func isEntryFieldSet(entry *wallabago.Item, field string) bool {
+	switch field {
+	case "archive":
+		return entry.IsArchived == 1
+	case "star":
+		return entry.IsStarred == 1
+	case "public":
+		return entry.IsPublic
+	}
+	return false
+}
+
+// Retrieve the status line, with wallabag host, user and profile if any.
+func getStatusLine(host, user, profile string) string {
+	if host == "" {
+		host = "unknown server"
+	}
+	status := "Connected to " + host
+	if user != "" {
+		status += " as " + user
+	}
+	if profile != "" {
+		status += " (profile " + profile + ")"
+	}
+	return status
+}
+
+// Retrieve a description of active filters.
+func getActiveFilters(filters walgotTableFilters) []string {
+	var active []string
+	if filters.ReadState != "" {
+		active = append(active, getReadStateLabel(filters.ReadState))
+	}
+	if filters.Starred {
+		active = append(active, "Starred")
+	}
+	if filters.Public {
+		active = append(active, "Public")
+	}
+	if filters.Search != "" {
+		active = append(active, "Search: "+filters.Search)
+	}
+	if filters.Tag != "" {
+		active = append(active, "Tag: "+filters.Tag)
+	}
+	if filters.ServerSearch != "" {
+		active = append(active, "Wallabag search: "+filters.ServerSearch)
+	}
+	if filters.DateRange != "" {
+		active = append(active, "Date: "+filters.DateRange)
+	}
+
+	return active
 }
 
 // Case insensitive strings.Contains:
@@ -103,28 +1180,74 @@ func isValidURL(u string) bool {
 	return true
 }
 
-func getContentForViewport(contentHTML string) string {
+// Convert HTML content to text.
+// Links are replaced by their number and listed at the end with linkReferences,
+// otherwise they are kept inline.
+func getContentForViewport(contentHTML string, linkReferences bool) string {
+	if !linkReferences {
+		return html2text.HTML2TextWithOptions(contentHTML, html2text.WithLinksInnerText())
+	}
+
 	content, links := getCleanedContentAndLinks(contentHTML)
 	content += "\r\n\r\n\r\n" + generateFootnoteLinks(links)
 
 	return content
 }
 
+// Render HTML content as styled markdown.
+// Links footnotes are added with linkReferences, so they can be opened by their number.
+// Boilerplate is removed from markdown before rendering.
+func getMarkdownContentForViewport(contentHTML string, wrapWidth int, linkReferences bool, trim *walgotTrimRules) (string, error) {
+	converter := md.NewConverter("", true, nil)
+	markdown, err := converter.ConvertString(contentHTML)
+	if err != nil {
+		return "", err
+	}
+	markdown = trimContent(markdown, trim)
+
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle("dark"),
+		glamour.WithWordWrap(wrapWidth),
+	)
+	if err != nil {
+		return "", err
+	}
+	content, err := renderer.Render(markdown)
+	if err != nil {
+		return "", err
+	}
+	if !linkReferences {
+		return content, nil
+	}
+
+	_, links := getCleanedContentAndLinks(contentHTML)
+	footnotes := generateFootnoteLinks(links)
+
+	return content + "\r\n\r\n" + wrap.String(wordwrap.String(footnotes, wrapWidth), wrapWidth), nil
+}
+
 func getCleanedContentAndLinks(contentHTML string) (string, []string) {
 	content := html2text.HTML2TextWithOptions(contentHTML, html2text.WithLinksInnerText())
 	return parseLinksInContent(content)
 }
 
 // parse links in the recieved string.
+// Links are numbered by order of appearance, a link used several times keeps its number.
 func parseLinksInContent(content string) (string, []string) {
 	var links []string
+	numbers := map[string]int{}
 
 	re := regexp.MustCompile("(?i)<((https?|gopher|gemini)://[^>]*)>")
 	// Find and loop over all matching strings.
 	results := re.FindAllStringSubmatch(content, -1)
 	for i := range results {
-		content = strings.Replace(content, results[i][0], "["+strconv.Itoa(i+1)+"]", 1)
-		links = append(links, results[i][1])
+		n, ok := numbers[results[i][1]]
+		if !ok {
+			links = append(links, results[i][1])
+			n = len(links)
+			numbers[results[i][1]] = n
+		}
+		content = strings.Replace(content, results[i][0], "["+strconv.Itoa(n)+"]", 1)
 	}
 
 	return content, links
@@ -2,9 +2,14 @@ package tui
 
 import (
 	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Strubbl/wallabago/v7"
+	"github.com/charmbracelet/bubbles/table"
 )
 
 func TestGetRequiredNbAPICalls(t *testing.T) {
@@ -14,9 +19,22 @@ func TestGetRequiredNbAPICalls(t *testing.T) {
 		expectedNbCalls            int
 	}{
 		{0, 0, 0},
+		{0, 100, 0},
+		{1, 100, 1},
 		{100, 10, 10},
 		{500, 255, 2},
 		{100, 100, 1},
+		{101, 100, 2},
+		{99, 100, 1},
+		{199, 100, 2},
+		{200, 100, 2},
+		{201, 100, 3},
+		{1, 1, 1},
+		{7, 1, 7},
+		{1000000, 300, 3334},
+		{1000000, 250, 4000},
+		{1000001, 250, 4001},
+		{2000000000, 7, 285714286},
 		{-1, 100, 0},
 		{100, -1, 1},
 	}
@@ -60,3 +78,1122 @@ func TestGetSelectedEntryIndex(t *testing.T) {
 		}
 	}
 }
+
+func TestGetEntryMetadataText(t *testing.T) {
+	date := &wallabago.WallabagTime{Time: time.Date(2022, 12, 5, 10, 30, 0, 0, time.UTC)}
+	var tests = []struct {
+		input    wallabago.Item
+		expected string
+	}{
+		{
+			wallabago.Item{URL: "https://a.org/post", DomainName: "a.org", CreatedAt: date, ReadingTime: 5, Tags: []wallabago.Tag{{Label: "go"}, {Label: "linux"}}},
+			"URL: https://a.org/post\nDomain: a.org\nCreated: 2022-12-05\nUpdated: \nEst. read: 5 min\nTags: go, linux\nStatus: unread",
+		},
+		{
+			wallabago.Item{URL: "https://b.org", CreatedAt: date, UpdatedAt: date, IsArchived: 1, IsStarred: 1, IsPublic: true, UID: "abc"},
+			"URL: https://b.org\nDomain: (no domain)\nCreated: 2022-12-05\nUpdated: 2022-12-05\nEst. read: " + formatReadingTime(0) + "\nTags: none\nStatus: archived, starred, public\nPublic link: https://wallabag.test/share/abc",
+		},
+	}
+
+	for _, test := range tests {
+		if result := getEntryMetadataText(&test.input, "2006-01-02", "https://wallabag.test"); result != test.expected {
+			t.Errorf("getEntryMetadataText(%v): expected %q, got %q", test.input.URL, test.expected, result)
+		}
+	}
+}
+
+func TestGetEntryUpdateMessage(t *testing.T) {
+	var tests = []struct {
+		inputPrevious   wallabago.Item
+		inputUpdated    wallabago.Item
+		expectedMessage string
+	}{
+		{wallabago.Item{IsStarred: 0}, wallabago.Item{IsStarred: 1}, "Entry starred"},
+		{wallabago.Item{IsStarred: 1}, wallabago.Item{IsStarred: 0}, "Entry unstarred"},
+		{wallabago.Item{IsStarred: 1}, wallabago.Item{IsStarred: 1}, "Entry has been updated"},
+		{wallabago.Item{IsArchived: 0}, wallabago.Item{IsArchived: 1}, "Entry archived"},
+		{wallabago.Item{IsArchived: 1}, wallabago.Item{IsArchived: 0}, "Entry marked as unread"},
+		{wallabago.Item{IsPublic: false}, wallabago.Item{IsPublic: true, UID: "abc"}, "Entry is now public: https://wallabag.test/share/abc"},
+		{wallabago.Item{IsPublic: false}, wallabago.Item{IsPublic: true}, "Entry is now public (no public link returned by wallabag)"},
+		{wallabago.Item{IsPublic: true, UID: "abc"}, wallabago.Item{IsPublic: false}, "Entry is not public anymore"},
+		{wallabago.Item{}, wallabago.Item{Tags: []wallabago.Tag{{Label: "go"}, {Label: "linux"}}}, "Tags updated: go, linux"},
+		{wallabago.Item{Tags: []wallabago.Tag{{Label: "go"}}}, wallabago.Item{}, "All tags removed"},
+	}
+
+	for _, test := range tests {
+		result := getEntryUpdateMessage(test.inputPrevious, test.inputUpdated, "https://wallabag.test")
+		if test.expectedMessage != result {
+			t.Errorf("getEntryUpdateMessage(%v, %v): expectedMessage %v, got %v", test.inputPrevious, test.inputUpdated, test.expectedMessage, result)
+		}
+	}
+}
+
+func TestParseTagsInput(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"", "[]"},
+		{" , ,", "[]"},
+		{"go", "[go]"},
+		{" go , linux,wallabag ", "[go linux wallabag]"},
+		{"Go, go, GO,linux", "[Go linux]"},
+	}
+
+	for _, test := range tests {
+		if result := fmt.Sprint(parseTagsInput(test.input)); result != test.expected {
+			t.Errorf("parseTagsInput(%v): expected %v, got %v", test.input, test.expected, result)
+		}
+	}
+}
+
+func TestHasTag(t *testing.T) {
+	entry := wallabago.Item{
+		Tags: []wallabago.Tag{
+			{ID: 1, Label: "Golang", Slug: "golang"},
+			{ID: 2, Label: "tui", Slug: "tui"},
+		},
+	}
+
+	var tests = []struct {
+		inputEntry     wallabago.Item
+		inputTag       string
+		expectedHasTag bool
+	}{
+		{entry, "golang", true},
+		{entry, "GOLANG", true},
+		{entry, "TUI", true},
+		{entry, "go", false},
+		{entry, "reading", false},
+		{wallabago.Item{}, "golang", false},
+	}
+
+	for _, test := range tests {
+		result := hasTag(&test.inputEntry, test.inputTag)
+		if test.expectedHasTag != result {
+			t.Errorf("hasTag(%v, %v): expectedHasTag %v, got %v", test.inputEntry.Tags, test.inputTag, test.expectedHasTag, result)
+		}
+	}
+}
+
+func TestFormatReadingTime(t *testing.T) {
+	var tests = []struct {
+		inputMinutes int
+		expected     string
+	}{
+		{-1, "< 1 min"},
+		{0, "< 1 min"},
+		{1, "1 min"},
+		{59, "59 min"},
+		{60, "1h00"},
+		{125, "2h05"},
+	}
+
+	for _, test := range tests {
+		result := formatReadingTime(test.inputMinutes)
+		if test.expected != result {
+			t.Errorf("formatReadingTime(%v): expected %v, got %v", test.inputMinutes, test.expected, result)
+		}
+	}
+}
+
+func TestGetTotalReadingTime(t *testing.T) {
+	entries := []wallabago.Item{
+		{ID: 1, ReadingTime: 10},
+		{ID: 2, ReadingTime: 0},
+		{ID: 3, ReadingTime: 125, IsArchived: 1},
+		{ID: 4, ReadingTime: -1},
+	}
+	var tests = []struct {
+		inputFilters walgotTableFilters
+		expected     int
+	}{
+		{walgotTableFilters{}, 135},
+		{walgotTableFilters{ReadState: "unread"}, 10},
+		{walgotTableFilters{ReadState: "archived"}, 125},
+		{walgotTableFilters{Starred: true}, 0},
+	}
+
+	for _, test := range tests {
+		if result := getTotalReadingTime(entries, test.inputFilters); result != test.expected {
+			t.Errorf("getTotalReadingTime(%v): expected %v, got %v", test.inputFilters, test.expected, result)
+		}
+	}
+}
+
+func TestFormatEntryDate(t *testing.T) {
+	date := &wallabago.WallabagTime{Time: time.Date(2022, 12, 5, 10, 30, 0, 0, time.UTC)}
+	recent := &wallabago.WallabagTime{Time: time.Now().Add(-3 * time.Hour)}
+	var tests = []struct {
+		inputTime     *wallabago.WallabagTime
+		inputLayout   string
+		inputRelative bool
+		expected      string
+	}{
+		{date, "2006-01-02", false, "2022-12-05"},
+		{date, "02/01/2006", false, "05/12/2022"},
+		{date, "Jan 2, 2006 15:04", false, "Dec 5, 2022 10:30"},
+		{recent, "2006-01-02", true, "3h ago"},
+		{nil, "2006-01-02", false, ""},
+		{nil, "2006-01-02", true, ""},
+		{&wallabago.WallabagTime{}, "2006-01-02", false, ""},
+	}
+
+	for _, test := range tests {
+		result := formatEntryDate(test.inputTime, test.inputLayout, test.inputRelative)
+		if test.expected != result {
+			t.Errorf("formatEntryDate(%v, %v, %v): expected %v, got %v", test.inputTime, test.inputLayout, test.inputRelative, test.expected, result)
+		}
+	}
+}
+
+func TestGetSelectedRowID(t *testing.T) {
+	columns := table.WithColumns([]table.Column{{Title: "ID", Width: 4}, {Title: "Title", Width: 10}})
+	rows := []table.Row{{"12", "One"}, {"34", "Two"}}
+	withRows := table.New(columns, table.WithRows(rows))
+	withRows.SetCursor(1)
+	// Rows removed, cursor is left on the previous position:
+	cleared := table.New(columns, table.WithRows(rows))
+	cleared.SetCursor(1)
+	cleared.SetRows(nil)
+	var tests = []struct {
+		name        string
+		input       table.Model
+		inputNbRows int
+		expected    int
+	}{
+		{"rows", withRows, 2, 34},
+		{"empty", table.New(columns), 0, 0},
+		{"cleared", cleared, 0, 0},
+	}
+
+	for _, test := range tests {
+		if result := getSelectedRowID(test.input, test.inputNbRows); result != test.expected {
+			t.Errorf("getSelectedRowID(%v): expected %v, got %v", test.name, test.expected, result)
+		}
+	}
+}
+
+func TestGetReadingWidths(t *testing.T) {
+	var tests = []struct {
+		inputReadingWidth     int
+		inputTermWidth        int
+		expectedViewportWidth int
+		expectedWrapWidth     int
+	}{
+		{0, 200, 80, 72},
+		{0, 75, 75, 72},
+		{0, 60, 60, 58},
+		{-1, 200, 80, 72},
+		{120, 200, 120, 120},
+		{120, 100, 100, 98},
+		{50, 200, 50, 50},
+		{0, 2, 2, 1},
+		{0, 0, 1, 1},
+	}
+
+	for _, test := range tests {
+		v, w := getReadingWidths(test.inputReadingWidth, test.inputTermWidth)
+		if v != test.expectedViewportWidth || w != test.expectedWrapWidth {
+			t.Errorf("getReadingWidths(%v, %v): expected %v/%v, got %v/%v", test.inputReadingWidth, test.inputTermWidth, test.expectedViewportWidth, test.expectedWrapWidth, v, w)
+		}
+	}
+}
+
+func TestSortEntries(t *testing.T) {
+	day := func(d int) *wallabago.WallabagTime {
+		return &wallabago.WallabagTime{Time: time.Date(2022, 12, d, 0, 0, 0, 0, time.UTC)}
+	}
+	entries := []wallabago.Item{
+		{ID: 1, Title: "b", ReadingTime: 5, CreatedAt: day(2), UpdatedAt: day(3)},
+		{ID: 2, Title: "A", ReadingTime: 1, CreatedAt: day(1), UpdatedAt: day(4)},
+		{ID: 3, Title: "c", ReadingTime: 5, CreatedAt: day(3), UpdatedAt: nil},
+		{ID: 4, Title: "a", ReadingTime: 2, CreatedAt: day(2), UpdatedAt: day(1)},
+	}
+
+	var tests = []struct {
+		inputSorts  walgotTableSorts
+		expectedIDs []int
+	}{
+		{walgotTableSorts{"created", "desc"}, []int{3, 4, 1, 2}},
+		{walgotTableSorts{"created", "asc"}, []int{2, 1, 4, 3}},
+		{walgotTableSorts{"updated", "desc"}, []int{2, 1, 4, 3}},
+		{walgotTableSorts{"title", "asc"}, []int{2, 4, 1, 3}},
+		{walgotTableSorts{"reading", "desc"}, []int{3, 1, 4, 2}},
+		{walgotTableSorts{"reading", "asc"}, []int{2, 4, 1, 3}},
+	}
+
+	for _, test := range tests {
+		sorted := append([]wallabago.Item{}, entries...)
+		sortEntries(sorted, test.inputSorts)
+		ids := []int{}
+		for _, e := range sorted {
+			ids = append(ids, e.ID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(test.expectedIDs) {
+			t.Errorf("sortEntries(%v): expectedIDs %v, got %v", test.inputSorts, test.expectedIDs, ids)
+		}
+	}
+}
+
+func TestGetTableColumnAt(t *testing.T) {
+	columns := []table.Column{
+		{Title: "ID", Width: 0},
+		{Title: "Status", Width: 6},
+		{Title: "Title", Width: 10},
+	}
+
+	var tests = []struct {
+		inputX        int
+		expectedIndex int
+	}{
+		{0, 1},
+		{7, 1},
+		{8, 2},
+		{19, 2},
+		{20, -1},
+		{-1, -1},
+	}
+
+	for _, test := range tests {
+		result := getTableColumnAt(columns, test.inputX)
+		if test.expectedIndex != result {
+			t.Errorf("getTableColumnAt(%v): expectedIndex %v, got %v", test.inputX, test.expectedIndex, result)
+		}
+	}
+}
+
+func TestParseLinksInContent(t *testing.T) {
+	var tests = []struct {
+		input           string
+		expectedContent string
+		expectedLinks   []string
+	}{
+		{"no link", "no link", nil},
+		{"a <https://a.com> b <http://b.com>", "a [1] b [2]", []string{"https://a.com", "http://b.com"}},
+		{"a <https://a.com> b <https://b.com> a <https://a.com>", "a [1] b [2] a [1]", []string{"https://a.com", "https://b.com"}},
+		{"g <gemini://c.org> <mailto:me>", "g [1] <mailto:me>", []string{"gemini://c.org"}},
+	}
+
+	for _, test := range tests {
+		content, links := parseLinksInContent(test.input)
+		if content != test.expectedContent {
+			t.Errorf("parseLinksInContent(%v): expectedContent %v, got %v", test.input, test.expectedContent, content)
+		}
+		if fmt.Sprint(links) != fmt.Sprint(test.expectedLinks) {
+			t.Errorf("parseLinksInContent(%v): expectedLinks %v, got %v", test.input, test.expectedLinks, links)
+		}
+	}
+}
+
+func TestGetAdjacentRowID(t *testing.T) {
+	rows := []table.Row{{"10", "a"}, {"20", "b"}, {"30", "c"}}
+	var tests = []struct {
+		inputID          int
+		inputOffset      int
+		expectedPosition int
+		expectedID       int
+	}{
+		{10, 1, 1, 20},
+		{20, 1, 2, 30},
+		{20, -1, 0, 10},
+		{30, 1, 2, 0},
+		{10, -1, 0, 0},
+		{40, 1, -1, 0},
+	}
+
+	for _, test := range tests {
+		position, id := getAdjacentRowID(rows, test.inputID, test.inputOffset)
+		if position != test.expectedPosition || id != test.expectedID {
+			t.Errorf("getAdjacentRowID(%v, %v): expected %v/%v, got %v/%v", test.inputID, test.inputOffset, test.expectedPosition, test.expectedID, position, id)
+		}
+	}
+}
+
+func TestGetReadProgressLine(t *testing.T) {
+	var tests = []struct {
+		inputWidth   int
+		inputPercent float64
+		expected     string
+	}{
+		{4, 0, "────"},
+		{4, 0.5, "━━──"},
+		{4, 1, "━━━━"},
+		{4, 1.5, "━━━━"},
+		{4, -1, "────"},
+		{0, 0.5, ""},
+	}
+
+	for _, test := range tests {
+		result := getReadProgressLine(test.inputWidth, test.inputPercent)
+		if test.expected != result {
+			t.Errorf("getReadProgressLine(%v, %v): expected %v, got %v", test.inputWidth, test.inputPercent, test.expected, result)
+		}
+	}
+}
+
+func TestGetFooterHint(t *testing.T) {
+	var tests = []struct {
+		inputView string
+		expected  string
+	}{
+		{"list", "r: reload -- R: read state -- Toggles: s: starred, p: public -- ?: help"},
+		{"detail", "n / N: next / previous -- A: archive -- S: star -- q: back -- ?: help"},
+		{"dialog", "enter: confirm -- esc: cancel"},
+		{"help", "q / esc: back"},
+		{"tags", "enter: filter by tag -- q / esc: back -- ?: help"},
+		{"profiles", "enter: switch profile -- q / esc: back"},
+		{"logs", "r: show new lines -- q / esc: back"},
+	}
+
+	for _, test := range tests {
+		if result := getFooterHint(test.inputView, defaultKeybindings); result != test.expected {
+			t.Errorf("getFooterHint(%v): expected %v, got %v", test.inputView, test.expected, result)
+		}
+	}
+}
+
+func TestGetProfileNames(t *testing.T) {
+	var tests = []struct {
+		input    map[string]string
+		expected []string
+	}{
+		{map[string]string{"": "credentials.json"}, []string{""}},
+		{map[string]string{"work": "work.json", "": "credentials.json", "home": "home.json"}, []string{"", "home", "work"}},
+		{map[string]string{"work": "work.json"}, []string{"work"}},
+	}
+
+	for _, test := range tests {
+		result := getProfileNames(test.input)
+		if fmt.Sprint(result) != fmt.Sprint(test.expected) {
+			t.Errorf("getProfileNames(%v): expected %v, got %v", test.input, test.expected, result)
+		}
+		// Labels and names are converted both ways:
+		for _, name := range result {
+			if getProfileName(getProfileLabel(name)) != name {
+				t.Errorf("getProfileName(getProfileLabel(%v)): expected %v, got %v", name, name, getProfileName(getProfileLabel(name)))
+			}
+		}
+	}
+}
+
+func TestGetReadState(t *testing.T) {
+	var tests = []struct {
+		inputUnread   bool
+		inputArchived bool
+		expected      string
+	}{
+		{false, false, ""},
+		{true, false, "unread"},
+		{false, true, "archived"},
+		{true, true, "unread"},
+	}
+
+	for _, test := range tests {
+		if result := getReadState(test.inputUnread, test.inputArchived); result != test.expected {
+			t.Errorf("getReadState(%v, %v): expected %v, got %v", test.inputUnread, test.inputArchived, test.expected, result)
+		}
+	}
+}
+
+func TestGetNextReadState(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"unread", "archived"},
+		{"archived", ""},
+		{"", "unread"},
+	}
+
+	for _, test := range tests {
+		if result := getNextReadState(test.input); result != test.expected {
+			t.Errorf("getNextReadState(%v): expected %v, got %v", test.input, test.expected, result)
+		}
+	}
+}
+
+func TestGetActiveFilters(t *testing.T) {
+	var tests = []struct {
+		inputFilters walgotTableFilters
+		expected     []string
+	}{
+		{walgotTableFilters{}, nil},
+		{walgotTableFilters{ReadState: "unread"}, []string{"Unread"}},
+		{walgotTableFilters{Starred: true, Public: true}, []string{"Starred", "Public"}},
+		{walgotTableFilters{ReadState: "archived", Tag: "go"}, []string{"Archived", "Tag: go"}},
+		{walgotTableFilters{ReadState: "archived", Starred: true}, []string{"Archived", "Starred"}},
+		{walgotTableFilters{Search: "foo", ServerSearch: "bar"}, []string{"Search: foo", "Wallabag search: bar"}},
+		{walgotTableFilters{Tag: "go", DateRange: "7d"}, []string{"Tag: go", "Date: 7d"}},
+	}
+
+	for _, test := range tests {
+		result := getActiveFilters(test.inputFilters)
+		if fmt.Sprint(result) != fmt.Sprint(test.expected) {
+			t.Errorf("getActiveFilters(%v): expected %v, got %v", test.inputFilters, test.expected, result)
+		}
+	}
+}
+
+func TestParseDateRange(t *testing.T) {
+	now := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	var tests = []struct {
+		input         string
+		expectedField string
+		expectedSince time.Time
+		expectedUntil time.Time
+		expectedError bool
+	}{
+		{"7d", "created", now.AddDate(0, 0, -7), time.Time{}, false},
+		{"2w", "created", now.AddDate(0, 0, -14), time.Time{}, false},
+		{"3m", "created", now.AddDate(0, -3, 0), time.Time{}, false},
+		{"1y", "created", now.AddDate(-1, 0, 0), time.Time{}, false},
+		{"updated:7d", "updated", now.AddDate(0, 0, -7), time.Time{}, false},
+		{"2024-01-01..2024-02-01", "created", day(2024, 1, 1), day(2024, 2, 2), false},
+		{"2024-01-01..", "created", day(2024, 1, 1), time.Time{}, false},
+		{"..2024-02-01", "created", time.Time{}, day(2024, 2, 2), false},
+		{"created: 2024-01-01", "created", day(2024, 1, 1), time.Time{}, false},
+		{"..", "created", time.Time{}, time.Time{}, true},
+		{"7x", "created", time.Time{}, time.Time{}, true},
+		{"2024-13-01", "created", time.Time{}, time.Time{}, true},
+		{"2024-02-01..2024-01-01", "created", time.Time{}, time.Time{}, true},
+	}
+
+	for _, test := range tests {
+		field, since, until, err := parseDateRange(test.input, now)
+		if (err != nil) != test.expectedError {
+			t.Errorf("parseDateRange(%v): expectedError %v, got %v", test.input, test.expectedError, err)
+			continue
+		}
+		if field != test.expectedField || !since.Equal(test.expectedSince) || !until.Equal(test.expectedUntil) {
+			t.Errorf("parseDateRange(%v): expected %v %v %v, got %v %v %v", test.input, test.expectedField, test.expectedSince, test.expectedUntil, field, since, until)
+		}
+	}
+}
+
+func TestIsEntryInDateRange(t *testing.T) {
+	entry := wallabago.Item{
+		CreatedAt: &wallabago.WallabagTime{Time: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)},
+		UpdatedAt: &wallabago.WallabagTime{Time: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)},
+	}
+	var tests = []struct {
+		inputFilters walgotTableFilters
+		expected     bool
+	}{
+		{walgotTableFilters{}, true},
+		{walgotTableFilters{Since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, true},
+		{walgotTableFilters{Since: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}, false},
+		{walgotTableFilters{DateField: "updated", Since: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}, true},
+		{walgotTableFilters{Until: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)}, false},
+		{walgotTableFilters{Until: time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)}, true},
+	}
+
+	for _, test := range tests {
+		if result := isEntryInDateRange(&entry, test.inputFilters); result != test.expected {
+			t.Errorf("isEntryInDateRange(%v): expected %v, got %v", test.inputFilters, test.expected, result)
+		}
+	}
+
+	// Entries without date don't match a date range:
+	if isEntryInDateRange(&wallabago.Item{}, walgotTableFilters{Since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}) {
+		t.Errorf("isEntryInDateRange(no date): expected false, got true")
+	}
+}
+
+func TestGetStatusLine(t *testing.T) {
+	var tests = []struct {
+		inputHost    string
+		inputUser    string
+		inputProfile string
+		expected     string
+	}{
+		{"wallabag.example.com", "bob", "", "Connected to wallabag.example.com as bob"},
+		{"wallabag.example.com", "", "", "Connected to wallabag.example.com"},
+		{"", "bob", "", "Connected to unknown server as bob"},
+		{"wallabag.example.com", "bob", "work", "Connected to wallabag.example.com as bob (profile work)"},
+	}
+
+	for _, test := range tests {
+		if result := getStatusLine(test.inputHost, test.inputUser, test.inputProfile); result != test.expected {
+			t.Errorf("getStatusLine(%v, %v, %v): expected %v, got %v", test.inputHost, test.inputUser, test.inputProfile, test.expected, result)
+		}
+	}
+}
+
+func TestGetMainHeight(t *testing.T) {
+	var tests = []struct {
+		inputHeight int
+		inputUsed   int
+		expected    int
+	}{
+		{30, 5, 25},
+		{6, 5, 1},
+		{5, 5, 1},
+		{2, 5, 1},
+		{-3, 2, 1},
+	}
+
+	for _, test := range tests {
+		if result := getMainHeight(test.inputHeight, test.inputUsed); result != test.expected {
+			t.Errorf("getMainHeight(%v, %v): expected %v, got %v", test.inputHeight, test.inputUsed, test.expected, result)
+		}
+	}
+}
+
+func TestIsTermTooSmall(t *testing.T) {
+	var tests = []struct {
+		input    termSize
+		expected bool
+	}{
+		{termSize{80, 24}, false},
+		{termSize{minTermWidth, minTermHeight}, false},
+		{termSize{minTermWidth - 1, 24}, true},
+		{termSize{80, minTermHeight - 1}, true},
+		{termSize{0, 0}, true},
+	}
+
+	for _, test := range tests {
+		if result := isTermTooSmall(test.input); result != test.expected {
+			t.Errorf("isTermTooSmall(%v): expected %v, got %v", test.input, test.expected, result)
+		}
+	}
+}
+
+func TestGetListWidth(t *testing.T) {
+	var tests = []struct {
+		inputListWidth int
+		inputTermWidth int
+		inputPreview   bool
+		expected       int
+	}{
+		{0, 120, false, 120},
+		{-1, 120, false, 120},
+		{100, 120, false, 100},
+		{100, 80, false, 80},
+		{0, 120, true, 120},
+		{0, 200, true, 120},
+		{0, 200, false, 200},
+		{100, 200, true, 100},
+	}
+
+	for _, test := range tests {
+		if result := getListWidth(test.inputListWidth, test.inputTermWidth, test.inputPreview); result != test.expected {
+			t.Errorf("getListWidth(%v, %v, %v): expected %v, got %v", test.inputListWidth, test.inputTermWidth, test.inputPreview, test.expected, result)
+		}
+	}
+}
+
+func TestGetPreviewWidth(t *testing.T) {
+	var tests = []struct {
+		inputListWidth int
+		inputTermWidth int
+		inputPreview   bool
+		expected       int
+	}{
+		{0, 120, true, 0},
+		{0, 200, true, 80},
+		{0, 200, false, 0},
+		{100, 120, true, 0},
+		{100, 180, true, 80},
+	}
+
+	for _, test := range tests {
+		if result := getPreviewWidth(test.inputListWidth, test.inputTermWidth, test.inputPreview); result != test.expected {
+			t.Errorf("getPreviewWidth(%v, %v, %v): expected %v, got %v", test.inputListWidth, test.inputTermWidth, test.inputPreview, test.expected, result)
+		}
+	}
+}
+
+func TestGetPreviewText(t *testing.T) {
+	entry := wallabago.Item{Title: "A title", Content: "<p>First paragraph</p><p></p><p>Second one</p>"}
+	var tests = []struct {
+		inputWidth  int
+		inputHeight int
+		expected    string
+	}{
+		{40, 10, "A title\n\nFirst paragraph\n\nSecond one"},
+		{40, 3, "A title\n\nFirst paragraph"},
+		{5, 4, "A\ntitle\n\nFirst"},
+		{0, 10, ""},
+	}
+
+	for _, test := range tests {
+		if result := getPreviewText(&entry, test.inputWidth, test.inputHeight); result != test.expected {
+			t.Errorf("getPreviewText(%v, %v): expected %q, got %q", test.inputWidth, test.inputHeight, test.expected, result)
+		}
+	}
+}
+
+func TestGetKeysPopupText(t *testing.T) {
+	var tests = []struct {
+		inputLines   []string
+		inputWidth   int
+		inputMaxRows int
+		expected     string
+	}{
+		{[]string{"a: one", "b: two", "c: three"}, 20, 5, "a: one\nb: two\nc: three"},
+		{[]string{"a: one", "b: two", "c: three"}, 80, 5, "a: one" + strings.Repeat(" ", 34) + "c: three\nb: two"},
+		{[]string{"a: one", "b: two", "c: three"}, 20, 2, "a: one\n…"},
+		{[]string{"a: a long description"}, 12, 2, "a: a long…"},
+		{nil, 20, 5, ""},
+		{[]string{"a: one"}, 20, 0, ""},
+	}
+
+	for _, test := range tests {
+		if result := getKeysPopupText(test.inputLines, test.inputWidth, test.inputMaxRows); result != test.expected {
+			t.Errorf("getKeysPopupText(%v, %v, %v): expected %q, got %q", test.inputLines, test.inputWidth, test.inputMaxRows, test.expected, result)
+		}
+	}
+}
+
+func TestGetLogsText(t *testing.T) {
+	var tests = []struct {
+		inputLines []string
+		inputWidth int
+		expected   string
+	}{
+		{nil, 20, ""},
+		{[]string{"one", "two"}, 20, "one\ntwo"},
+		{[]string{"a long log line", "short"}, 6, "a long\nlog\nline\nshort"},
+		{[]string{"abcdefgh"}, 4, "abcd\nefgh"},
+	}
+
+	for _, test := range tests {
+		if result := getLogsText(test.inputLines, test.inputWidth); result != test.expected {
+			t.Errorf("getLogsText(%v, %v): expected %q, got %q", test.inputLines, test.inputWidth, test.expected, result)
+		}
+	}
+}
+
+func TestGetExcerpt(t *testing.T) {
+	var tests = []struct {
+		inputContent   string
+		inputMaxLength int
+		expected       string
+	}{
+		{"<p>Short text</p>", 60, "Short text"},
+		{"<h1>Title</h1>\n<p>First   paragraph</p>", 60, "Title First paragraph"},
+		{"<p>Some words to cut</p>", 12, "Some words…"},
+		{"<p>Some words to cut</p>", 10, "Some words…"},
+		{"<p>Averyveryverylongword</p>", 6, "Averyv…"},
+		{"", 60, ""},
+	}
+
+	for _, test := range tests {
+		if result := getExcerpt(test.inputContent, test.inputMaxLength); result != test.expected {
+			t.Errorf("getExcerpt(%v, %v): expected %q, got %q", test.inputContent, test.inputMaxLength, test.expected, result)
+		}
+	}
+}
+
+func TestGetRandomEntryID(t *testing.T) {
+	var tests = []struct {
+		inputEntries []wallabago.Item
+		expectedIDs  []int
+	}{
+		{[]wallabago.Item{}, []int{0}},
+		{[]wallabago.Item{{ID: 1, IsArchived: 1}, {ID: 2}, {ID: 3, IsArchived: 1}}, []int{2}},
+		{[]wallabago.Item{{ID: 1, IsArchived: 1}, {ID: 3, IsArchived: 1}}, []int{1, 3}},
+		{[]wallabago.Item{{ID: 1}, {ID: 2, IsArchived: 1}, {ID: 3}}, []int{1, 3}},
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range tests {
+		for i := 0; i < 10; i++ {
+			result := getRandomEntryID(test.inputEntries, rnd)
+			found := false
+			for _, id := range test.expectedIDs {
+				found = found || id == result
+			}
+			if !found {
+				t.Errorf("getRandomEntryID(%v): expected one of %v, got %v", test.inputEntries, test.expectedIDs, result)
+			}
+		}
+	}
+}
+
+func TestGetJumpPosition(t *testing.T) {
+	rows := []table.Row{{"12"}, {"7"}, {"42"}}
+	var tests = []struct {
+		input            string
+		expected         int
+		expectedIsErrNil bool
+	}{
+		{"1", 0, true},
+		{" 3 ", 2, true},
+		{"#42", 2, true},
+		{"# 7", 1, true},
+		{"0", -1, false},
+		{"4", -1, false},
+		{"#5", -1, false},
+		{"#", -1, false},
+		{"abc", -1, false},
+	}
+
+	for _, test := range tests {
+		result, err := getJumpPosition(rows, test.input)
+		if result != test.expected {
+			t.Errorf("getJumpPosition(%v): expected %v, got %v", test.input, test.expected, result)
+		}
+		if (err == nil) != test.expectedIsErrNil {
+			t.Errorf("getJumpPosition(%v): expectedIsErrNil %v, got %v", test.input, test.expectedIsErrNil, err)
+		}
+	}
+}
+
+func TestGetHalfPage(t *testing.T) {
+	var tests = []struct {
+		inputHeight int
+		expected    int
+	}{
+		{40, 20},
+		{41, 20},
+		{2, 1},
+		{1, 1},
+		{0, 1},
+		{-5, 1},
+	}
+
+	for _, test := range tests {
+		if result := getHalfPage(test.inputHeight); result != test.expected {
+			t.Errorf("getHalfPage(%v): expected %v, got %v", test.inputHeight, test.expected, result)
+		}
+	}
+}
+
+func TestGetPageJump(t *testing.T) {
+	var tests = []struct {
+		inputPageJumpRows int
+		inputHeight       int
+		expected          int
+	}{
+		{0, 30, 30},
+		{0, 0, 1},
+		{10, 30, 10},
+		{-1, 30, 30},
+	}
+
+	for _, test := range tests {
+		if result := getPageJump(test.inputPageJumpRows, test.inputHeight); result != test.expected {
+			t.Errorf("getPageJump(%v, %v): expected %v, got %v", test.inputPageJumpRows, test.inputHeight, test.expected, result)
+		}
+	}
+}
+
+func TestGetLoadingText(t *testing.T) {
+	var tests = []struct {
+		inputTotal  int
+		inputLoaded int
+		expected    string
+	}{
+		{0, 0, "Loading all entries from wallabag…"},
+		{540, 0, "Loading all 540 entries from wallabag…"},
+		{540, 220, "Loaded 220 / 540 entries from wallabag…"},
+		{540, 540, "Loaded 540 / 540 entries from wallabag…"},
+	}
+
+	for _, test := range tests {
+		if result := getLoadingText(test.inputTotal, test.inputLoaded); result != test.expected {
+			t.Errorf("getLoadingText(%v, %v): expected %v, got %v", test.inputTotal, test.inputLoaded, test.expected, result)
+		}
+	}
+}
+
+func TestGetServerCountText(t *testing.T) {
+	var tests = []struct {
+		inputServerCount int
+		inputLoaded      int
+		expected         string
+	}{
+		{0, 540, ""},
+		{540, 540, " (540 on server)"},
+		{545, 540, " (545 on server, 5 new)"},
+		{538, 540, " (538 on server, 2 removed)"},
+	}
+
+	for _, test := range tests {
+		if result := getServerCountText(test.inputServerCount, test.inputLoaded); result != test.expected {
+			t.Errorf("getServerCountText(%v, %v): expected %v, got %v", test.inputServerCount, test.inputLoaded, test.expected, result)
+		}
+	}
+}
+
+func TestGetServerSearchMessage(t *testing.T) {
+	var tests = []struct {
+		inputMatches int
+		inputShown   int
+		expected     string
+	}{
+		{3, 3, "3 matches on wallabag for go"},
+		{3, 1, "3 matches on wallabag for go, 1 shown with current filters"},
+		{3, 0, "3 matches on wallabag for go, 0 shown with current filters"},
+	}
+
+	for _, test := range tests {
+		if result := getServerSearchMessage(test.inputMatches, test.inputShown, "go"); result != test.expected {
+			t.Errorf("getServerSearchMessage(%v, %v): expected %v, got %v", test.inputMatches, test.inputShown, test.expected, result)
+		}
+	}
+}
+
+func TestCountUnseenEntries(t *testing.T) {
+	entries := []wallabago.Item{{ID: 1}, {ID: 2}, {ID: 3}}
+	var tests = []struct {
+		inputSeen map[int]bool
+		expected  int
+	}{
+		{nil, 3},
+		{map[int]bool{1: true, 2: true, 3: true}, 0},
+		{map[int]bool{1: true, 4: true}, 2},
+	}
+
+	for _, test := range tests {
+		if result := countUnseenEntries(entries, test.inputSeen); result != test.expected {
+			t.Errorf("countUnseenEntries(%v): expected %v, got %v", test.inputSeen, test.expected, result)
+		}
+	}
+}
+
+func TestGetNewEntriesText(t *testing.T) {
+	var tests = []struct {
+		input    int
+		expected string
+	}{
+		{1, "1 new article on wallabag"},
+		{3, "3 new articles on wallabag"},
+	}
+
+	for _, test := range tests {
+		if result := getNewEntriesText(test.input); result != test.expected {
+			t.Errorf("getNewEntriesText(%v): expected %v, got %v", test.input, test.expected, result)
+		}
+	}
+}
+
+func TestGetQueuedChangeText(t *testing.T) {
+	var tests = []struct {
+		inputOffline bool
+		inputPending int
+		expected     string
+	}{
+		{true, 1, "Offline, change will be sent to wallabag later (1 pending)"},
+		{false, 3, "Couldn't reach wallabag, change will be sent later (3 pending)"},
+	}
+
+	for _, test := range tests {
+		if result := getQueuedChangeText(test.inputOffline, test.inputPending); result != test.expected {
+			t.Errorf("getQueuedChangeText(%v, %v): expected %v, got %v", test.inputOffline, test.inputPending, test.expected, result)
+		}
+	}
+}
+
+func TestGetSentChangesText(t *testing.T) {
+	var tests = []struct {
+		inputSent      int
+		inputConflicts int
+		inputPending   int
+		expected       string
+	}{
+		{2, 0, 0, "2 pending changes sent to wallabag"},
+		{1, 1, 0, "1 pending changes sent to wallabag, 1 changed on wallabag since (wallabag version kept)"},
+		{1, 0, 2, "1 pending changes sent to wallabag, 2 still pending"},
+		{0, 0, 3, "Couldn't reach wallabag, 3 changes still pending"},
+	}
+
+	for _, test := range tests {
+		if result := getSentChangesText(test.inputSent, test.inputConflicts, test.inputPending); result != test.expected {
+			t.Errorf("getSentChangesText(%v, %v, %v): expected %v, got %v", test.inputSent, test.inputConflicts, test.inputPending, test.expected, result)
+		}
+	}
+}
+
+func TestGetHighlightTerm(t *testing.T) {
+	var tests = []struct {
+		inputArticleSearch string
+		inputFilters       walgotTableFilters
+		expected           string
+	}{
+		{"", walgotTableFilters{}, ""},
+		{"", walgotTableFilters{Search: "go"}, "go"},
+		{"", walgotTableFilters{ServerSearch: "rust"}, "rust"},
+		{"", walgotTableFilters{Search: "go", ServerSearch: "rust"}, "go"},
+		{"zig", walgotTableFilters{Search: "go", ServerSearch: "rust"}, "zig"},
+	}
+
+	for _, test := range tests {
+		if result := getHighlightTerm(test.inputArticleSearch, test.inputFilters); result != test.expected {
+			t.Errorf("getHighlightTerm(%v, %v): expected %v, got %v", test.inputArticleSearch, test.inputFilters, test.expected, result)
+		}
+	}
+}
+
+func TestGetMatchLines(t *testing.T) {
+	content := "Tags: go\n\nSome \x1b[1mGo\x1b[0m text\nnothing\ngo, go"
+	var tests = []struct {
+		inputTerm string
+		expected  []int
+	}{
+		{"", nil},
+		{"go", []int{0, 2, 4}},
+		{"some go", []int{2}},
+		{"rust", nil},
+	}
+
+	for _, test := range tests {
+		if result := getMatchLines(content, test.inputTerm); fmt.Sprint(result) != fmt.Sprint(test.expected) {
+			t.Errorf("getMatchLines(%v): expected %v, got %v", test.inputTerm, test.expected, result)
+		}
+	}
+}
+
+func TestGetFirstMatchFrom(t *testing.T) {
+	lines := []int{3, 10, 25}
+	var tests = []struct {
+		input    int
+		expected int
+	}{
+		{0, 0},
+		{3, 0},
+		{4, 1},
+		{25, 2},
+		{30, 0},
+	}
+
+	for _, test := range tests {
+		if result := getFirstMatchFrom(lines, test.input); result != test.expected {
+			t.Errorf("getFirstMatchFrom(%v): expected %v, got %v", test.input, test.expected, result)
+		}
+	}
+}
+
+func TestGetArticleSearchText(t *testing.T) {
+	var tests = []struct {
+		input    walgotArticleSearch
+		expected string
+	}{
+		{walgotArticleSearch{}, ""},
+		{walgotArticleSearch{Term: "go"}, "no match"},
+		{walgotArticleSearch{Term: "go", Lines: []int{1, 5, 8}, Current: 1}, "match 2/3"},
+	}
+
+	for _, test := range tests {
+		if result := getArticleSearchText(test.input); result != test.expected {
+			t.Errorf("getArticleSearchText(%v): expected %v, got %v", test.input, test.expected, result)
+		}
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	highlight := func(s string) string { return "[" + s + "]" }
+	var tests = []struct {
+		inputText string
+		inputTerm string
+		expected  string
+	}{
+		{"Some text", "", "Some text"},
+		{"Some text", "none", "Some text"},
+		{"Go is go, GO!", "go", "[Go] is [go], [GO]!"},
+		{"aaaa", "aa", "[aaaa]"},
+		{"abab ab", "ab", "[abab] [ab]"},
+		{"some\ntext", "some text", "some\ntext"},
+		{"Été été", "ÉTÉ", "[Été] [été]"},
+		{"\x1b[1mbold\x1b[0m text", "bold", "\x1b[1m[bold]\x1b[0m text"},
+		{"\x1b[1mte\x1b[0mxt", "text", "\x1b[1mte\x1b[0mxt"},
+	}
+
+	for _, test := range tests {
+		if result := highlightMatches(test.inputText, test.inputTerm, highlight); result != test.expected {
+			t.Errorf("highlightMatches(%q, %v): expected %q, got %q", test.inputText, test.inputTerm, test.expected, result)
+		}
+	}
+}
+
+func TestGetAnnotationsText(t *testing.T) {
+	var tests = []struct {
+		inputAnnotations []wallabago.Annotation
+		inputWrapWidth   int
+		expected         string
+	}{
+		{nil, 72, ""},
+		{[]wallabago.Annotation{{Quote: "Some text"}}, 72, "Annotations:\n\n> Some text"},
+		{[]wallabago.Annotation{{Quote: " Some text ", Text: "A note"}, {Quote: "Other"}}, 72, "Annotations:\n\n> Some text\nA note\n\n> Other"},
+		{[]wallabago.Annotation{{Quote: "Some long text"}}, 11, "Annotations:\n\n> Some long\n> text"},
+	}
+
+	for _, test := range tests {
+		if result := getAnnotationsText(test.inputAnnotations, test.inputWrapWidth); result != test.expected {
+			t.Errorf("getAnnotationsText(%v, %v): expected %q, got %q", test.inputAnnotations, test.inputWrapWidth, test.expected, result)
+		}
+	}
+}
+
+func TestGetTagCounts(t *testing.T) {
+	var tests = []struct {
+		inputEntries []wallabago.Item
+		expected     string
+	}{
+		{[]wallabago.Item{}, "[]"},
+		{[]wallabago.Item{{ID: 1}}, "[]"},
+		{
+			[]wallabago.Item{
+				{ID: 1, Tags: []wallabago.Tag{{Label: "go"}, {Label: "Linux"}}},
+				{ID: 2, Tags: []wallabago.Tag{{Label: "linux"}, {Label: "LINUX"}}},
+				{ID: 3, Tags: []wallabago.Tag{{Label: "Ansible"}}},
+			},
+			"[{Ansible 1} {go 1} {Linux 2}]",
+		},
+	}
+
+	for _, test := range tests {
+		if result := fmt.Sprint(getTagCounts(test.inputEntries)); result != test.expected {
+			t.Errorf("getTagCounts(%v): expected %v, got %v", test.inputEntries, test.expected, result)
+		}
+	}
+}
+
+func TestIsEntryFieldSet(t *testing.T) {
+	var tests = []struct {
+		inputEntry wallabago.Item
+		inputField string
+		expected   bool
+	}{
+		{wallabago.Item{IsArchived: 1}, "archive", true},
+		{wallabago.Item{IsArchived: 0}, "archive", false},
+		{wallabago.Item{IsStarred: 1}, "star", true},
+		{wallabago.Item{IsStarred: 0, IsArchived: 1}, "star", false},
+		{wallabago.Item{IsPublic: true}, "public", true},
+		{wallabago.Item{IsPublic: true}, "unknown", false},
+	}
+
+	for _, test := range tests {
+		if result := isEntryFieldSet(&test.inputEntry, test.inputField); result != test.expected {
+			t.Errorf("isEntryFieldSet(%v, %v): expected %v, got %v", test.inputEntry.ID, test.inputField, test.expected, result)
+		}
+	}
+}
+
+func TestGetMarkedEntriesURLs(t *testing.T) {
+	entries := []wallabago.Item{
+		{ID: 3, URL: "https://example.com/3"},
+		{ID: 1, URL: "https://example.com/1"},
+		{ID: 2, URL: "https://example.com/2"},
+	}
+	var tests = []struct {
+		inputMarked map[int]bool
+		expected    []string
+	}{
+		{map[int]bool{}, nil},
+		{map[int]bool{1: true, 3: true}, []string{"https://example.com/3", "https://example.com/1"}},
+		{map[int]bool{2: true, 42: true}, []string{"https://example.com/2"}},
+	}
+
+	for _, test := range tests {
+		result := getMarkedEntriesURLs(entries, test.inputMarked)
+		if fmt.Sprint(result) != fmt.Sprint(test.expected) {
+			t.Errorf("getMarkedEntriesURLs(%v): expected %v, got %v", test.inputMarked, test.expected, result)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"fmt"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// RelativeTime returns a human readable duration between t and now,
+// eg: "3h ago", "yesterday", "2 weeks ago" or "in 5 days" for future times.
+// Zero time returns an empty string.
+func RelativeTime(t, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+
+	var span string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		span = fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < day:
+		span = fmt.Sprintf("%dh", int(d/time.Hour))
+	case d < 2*day:
+		if future {
+			return "tomorrow"
+		}
+		return "yesterday"
+	case d < 7*day:
+		span = plural(int(d/day), "day")
+	case d < 30*day:
+		span = plural(int(d/(7*day)), "week")
+	case d < 365*day:
+		span = plural(int(d/(30*day)), "month")
+	default:
+		span = plural(int(d/(365*day)), "year")
+	}
+
+	if future {
+		return "in " + span
+	}
+	return span + " ago"
+}
+
+// Return "1 unit" or "n units".
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
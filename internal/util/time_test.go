@@ -0,0 +1,39 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2022, 12, 15, 12, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		input    time.Time
+		expected string
+	}{
+		{time.Time{}, ""},
+		{now, "just now"},
+		{now.Add(-30 * time.Second), "just now"},
+		{now.Add(-5 * time.Minute), "5m ago"},
+		{now.Add(-3 * time.Hour), "3h ago"},
+		{now.Add(-30 * time.Hour), "yesterday"},
+		{now.Add(-3 * day), "3 days ago"},
+		{now.Add(-7 * day), "1 week ago"},
+		{now.Add(-15 * day), "2 weeks ago"},
+		{now.Add(-45 * day), "1 month ago"},
+		{now.Add(-200 * day), "6 months ago"},
+		{now.Add(-400 * day), "1 year ago"},
+		{time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), "52 years ago"},
+		{now.Add(30 * time.Second), "just now"},
+		{now.Add(2 * time.Hour), "in 2h"},
+		{now.Add(36 * time.Hour), "tomorrow"},
+		{now.Add(10 * day), "in 1 week"},
+	}
+
+	for _, test := range tests {
+		result := RelativeTime(test.input, now)
+		if test.expected != result {
+			t.Errorf("RelativeTime(%v): expected %v, got %v", test.input, test.expected, result)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"git.bacardi55.io/bacardi55/walgot/cmd"
 )
@@ -10,6 +11,7 @@ import (
 func main() {
 	if c, e := cmd.Init(); e != nil {
 		fmt.Println("Error loading walgot", e)
+		os.Exit(1)
 	} else {
 		c.Run()
 	}